@@ -0,0 +1,91 @@
+package sensitive
+
+import "reflect"
+
+// AutoDiveMode defines which untagged struct fields are automatically dived into.
+type AutoDiveMode int
+
+const (
+	// AutoDiveOff requires an explicit `dive` tag to dive into nested structs. It is the default mode.
+	AutoDiveOff AutoDiveMode = iota
+
+	// AutoDiveEmbedded dives into untagged embedded structs whose types contain sensitive fields.
+	AutoDiveEmbedded
+
+	// AutoDiveAll dives into all untagged struct fields, including pointers and collections of structs,
+	// whose types contain sensitive fields.
+	AutoDiveAll
+)
+
+var (
+	tagAutoDive = "autodive"
+
+	autoDive AutoDiveMode
+)
+
+// SetAutoDive sets the auto dive mode of all struct types.
+//
+// The mode can also be enabled for a particular struct type using a blank field:
+//
+//	type Order struct {
+//		_ struct{} `sensitive:"autodive,scope=all"`
+//		...
+//	}
+//
+// The scope option is either `embedded` (default) or `all`.
+//
+// SetAutoDive resets the internal cache of scanned struct types.
+// It is intended to be called once at program initialization, before any struct is scanned.
+func SetAutoDive(mode AutoDiveMode) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	autoDive = mode
+	cache = make(map[reflect.Type]*sensitiveStructType)
+}
+
+// structAutoDive returns the auto dive mode of the given struct type,
+// which is the widest of the global mode and the one set at the struct level.
+func structAutoDive(rt reflect.Type) AutoDiveMode {
+	mode := autoDive
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Name != "_" {
+			continue
+		}
+		tag, _ := extractTag(field.Tag)
+		name, opts := parseTag(tag)
+		if name != tagAutoDive {
+			continue
+		}
+		m := AutoDiveEmbedded
+		if opts["scope"] == "all" {
+			m = AutoDiveAll
+		}
+		mode = max(mode, m)
+	}
+	return mode
+}
+
+// isAutoDive reports whether the given untagged field must be automatically dived into.
+func isAutoDive(mode AutoDiveMode, field reflect.StructField) bool {
+	switch {
+	case mode == AutoDiveOff:
+		return false
+	case mode == AutoDiveEmbedded && !field.Anonymous:
+		return false
+	}
+
+	tt := field.Type
+	if tt.Kind() == reflect.Pointer {
+		tt = tt.Elem()
+	}
+	switch tt.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		tt = tt.Elem()
+	}
+	if tt.Kind() == reflect.Pointer {
+		tt = tt.Elem()
+	}
+	return tt.Kind() == reflect.Struct
+}
@@ -0,0 +1,214 @@
+package sensitive
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAutoDive(t *testing.T) {
+	type Meta struct {
+		CreatedAt time.Time
+		Role      string
+	}
+	type Node struct {
+		Name string `sensitive:"data"`
+		Next *Node
+	}
+
+	type Tree struct {
+		Meta     Meta
+		Children []Tree
+	}
+
+	type tc struct {
+		mode  AutoDiveMode
+		val   func() any
+		want  any
+		found bool
+	}
+
+	type Embedded struct {
+		Address
+		Meta
+	}
+	embedded := func() any {
+		return &Embedded{
+			Address: Address{Street: "07024 Quigley Trace"},
+			Meta:    Meta{Role: "Teacher"},
+		}
+	}
+
+	type Fields struct {
+		Address   Address
+		Addresses []*Address
+		Contacts  map[string]Address
+		Meta      *Meta
+		Node      *Node
+	}
+	fields := func() any {
+		return &Fields{
+			Address:   Address{Street: "07024 Quigley Trace"},
+			Addresses: []*Address{{Street: "7234 Antone Springs"}},
+			Contacts:  map[string]Address{"A": {Street: "90 Kerluke Pine"}},
+			Meta:      &Meta{Role: "Teacher"},
+			Node:      &Node{Name: "Kenna", Next: &Node{Name: "Kenna31"}},
+		}
+	}
+
+	type Marked struct {
+		_       struct{} `sensitive:"autodive"`
+		Address `json:"address"`
+		Other   Address
+	}
+	type MarkedAll struct {
+		_     struct{} `sensitive:"autodive,scope=all"`
+		Other Address
+	}
+
+	tcs := []tc{
+		{
+			mode: AutoDiveOff,
+			val:  embedded,
+			want: embedded(),
+		},
+		{
+			mode: AutoDiveEmbedded,
+			val:  embedded,
+			want: &Embedded{
+				Address: Address{Street: "*******************"},
+				Meta:    Meta{Role: "Teacher"},
+			},
+			found: true,
+		},
+		{
+			mode: AutoDiveEmbedded,
+			val:  fields,
+			want: fields(),
+		},
+		{
+			mode: AutoDiveAll,
+			val:  fields,
+			want: &Fields{
+				Address:   Address{Street: "*******************"},
+				Addresses: []*Address{{Street: "*******************"}},
+				Contacts:  map[string]Address{"A": {Street: "***************"}},
+				Meta:      &Meta{Role: "Teacher"},
+				Node:      &Node{Name: "*****", Next: &Node{Name: "*******"}},
+			},
+			found: true,
+		},
+		{
+			mode: AutoDiveOff,
+			val: func() any {
+				return &Marked{
+					Address: Address{Street: "07024 Quigley Trace"},
+					Other:   Address{Street: "7234 Antone Springs"},
+				}
+			},
+			want: &Marked{
+				Address: Address{Street: "*******************"},
+				Other:   Address{Street: "7234 Antone Springs"},
+			},
+			found: true,
+		},
+		{
+			mode: AutoDiveOff,
+			val: func() any {
+				return &MarkedAll{
+					Other: Address{Street: "7234 Antone Springs"},
+				}
+			},
+			want: &MarkedAll{
+				Other: Address{Street: "*******************"},
+			},
+			found: true,
+		},
+		{
+			mode: AutoDiveAll,
+			val: func() any {
+				return &Tree{
+					Meta:     Meta{Role: "Teacher"},
+					Children: []Tree{{Meta: Meta{Role: "Student"}}},
+				}
+			},
+			want: &Tree{
+				Meta:     Meta{Role: "Teacher"},
+				Children: []Tree{{Meta: Meta{Role: "Student"}}},
+			},
+			found: false,
+		},
+	}
+
+	defer SetAutoDive(AutoDiveOff)
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			SetAutoDive(tc.mode)
+
+			val := tc.val()
+			found, err := Check(val)
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if found != tc.found {
+				t.Fatalf("expect found be %v, got %v", tc.found, found)
+			}
+
+			if err := Redact(val); err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, val) {
+				t.Fatalf("want %+v, got %+v", tc.want, val)
+			}
+		})
+	}
+}
+
+type autoDiveX struct {
+	Secret string `sensitive:"data"`
+	Y      autoDiveY
+}
+
+type autoDiveY struct {
+	X *autoDiveX
+}
+
+func TestAutoDive_ScanOrder(t *testing.T) {
+	defer SetAutoDive(AutoDiveOff)
+
+	newX := func() *autoDiveX {
+		return &autoDiveX{
+			Secret: "outer",
+			Y:      autoDiveY{X: &autoDiveX{Secret: "inner"}},
+		}
+	}
+	want := &autoDiveX{
+		Secret: "*****",
+		Y:      autoDiveY{X: &autoDiveX{Secret: "*****"}},
+	}
+
+	// the result must not depend on which one of the mutually referencing types is scanned first.
+	for i, first := range []any{autoDiveY{}, autoDiveX{}} {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			SetAutoDive(AutoDiveAll)
+
+			found, err := Check(first)
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !found {
+				t.Fatalf("expect %T be sensitive", first)
+			}
+
+			val := newX()
+			if err := Redact(val); err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(want, val) {
+				t.Fatalf("want %+v, got %+v", want, val)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ln80/struct-sensitive/internal/option"
+)
+
+var (
+	ErrBulkRedactMismatch = errors.New("bulk redact function must return one value per sensitive field")
+)
+
+// BulkReplaceFunc is a callback function executed by [RedactMany] with all the sensitive values of a batch.
+// It returns the new values in the same order as the given fields.
+type BulkReplaceFunc func(ctx context.Context, fields []FieldValue) ([]string, error)
+
+// RedactMany redacts the sensitive data of each struct in the given slice.
+// The slice elements are either structs or struct pointers.
+//
+// If [RedactConfig.BulkRedactFunc] is set, the sensitive values of all the structs are collected
+// and passed to it in a single call, then the returned values are written back. This is useful
+// when the redaction relies on a remote service such as a vault or a KMS.
+// Otherwise, each struct is redacted the same way as [Redact] does.
+func RedactMany[T any](structs []T, opts ...func(*RedactConfig)) error {
+	return RedactManyContext(context.Background(), structs, opts...)
+}
+
+// RedactManyContext is like [RedactMany] but passes the given context to the redact functions.
+func RedactManyContext[T any](ctx context.Context, structs []T, opts ...func(*RedactConfig)) error {
+	cfg := RedactConfig{
+		RedactScalarFunc: RedactScalarDefaultFunc,
+		RedactBytesFunc:  RedactBytesDefaultFunc,
+	}
+	option.Apply(&cfg, opts)
+
+	ptrs := make([]any, len(structs))
+	for i := range structs {
+		if reflect.TypeFor[T]().Kind() == reflect.Pointer {
+			ptrs[i] = structs[i]
+			continue
+		}
+		ptrs[i] = &structs[i]
+	}
+
+	if cfg.BulkRedactFunc == nil {
+		for _, ptr := range ptrs {
+			if err := RedactContext(ctx, ptr, opts...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	accessors := make([]Struct, 0, len(ptrs))
+	counts := make([]int, 0, len(ptrs))
+	var fields []FieldValue
+	for _, ptr := range ptrs {
+		accessor, err := Scan(ptr, cfg.RequireSubjectID, cfg.scanOption)
+		if err != nil {
+			return err
+		}
+		if !accessor.HasSensitive() {
+			continue
+		}
+		collected, err := accessor.Collect()
+		if err != nil {
+			return err
+		}
+		// values that implement Redactable or Maskable are redacted by their own methods.
+		values := collected[:0]
+		for _, v := range collected {
+			if !v.hasHook() {
+				values = append(values, v)
+			}
+		}
+		accessors = append(accessors, accessor)
+		counts = append(counts, len(values))
+		fields = append(fields, values...)
+	}
+	var newValues []string
+	if len(fields) > 0 {
+		var err error
+		newValues, err = cfg.BulkRedactFunc(ctx, fields)
+		if err != nil {
+			return err
+		}
+		if len(newValues) != len(fields) {
+			return fmt.Errorf("%w: got %d values for %d fields", ErrBulkRedactMismatch, len(newValues), len(fields))
+		}
+	}
+
+	// map key collisions don't stop the redaction; they are reported once all the structs are redacted.
+	var fieldErrs []error
+	offset := 0
+	for i, accessor := range accessors {
+		values := make(map[string]string, counts[i])
+		for j := offset; j < offset+counts[i]; j++ {
+			values[fields[j].Path] = newValues[j]
+		}
+		offset += counts[i]
+
+		errs, err := accessor.replaceContext(ctx, func(_ context.Context, fr FieldReplace, val string) (string, error) {
+			if newVal, ok := values[fr.Path]; ok {
+				return newVal, nil
+			}
+			return val, nil
+		})
+		if err != nil {
+			return err
+		}
+		fieldErrs = append(fieldErrs, errs...)
+		typedErrs, err := redactTyped(ctx, accessor, cfg, nil)
+		if err != nil {
+			return err
+		}
+		fieldErrs = append(fieldErrs, typedErrs...)
+	}
+	return errors.Join(fieldErrs...)
+}
@@ -0,0 +1,148 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRedactMany(t *testing.T) {
+	type tc struct {
+		val    []*Profile
+		want   []*Profile
+		option func(*RedactConfig)
+		calls  int
+		ok     bool
+		err    error
+	}
+
+	profiles := func() []*Profile {
+		return []*Profile{
+			{
+				ID:    "abc",
+				Email: "email@example.com",
+				Devices: []Device{
+					{IPAddr: "169.251.207.194"},
+				},
+			},
+			nil,
+			{
+				ID:       "def",
+				Fullname: "Guadalupe Kemmer DDS",
+			},
+		}
+	}
+
+	calls := 0
+	bulkUpper := func(rc *RedactConfig) {
+		rc.RequireSubjectID = true
+		rc.BulkRedactFunc = func(ctx context.Context, fields []FieldValue) ([]string, error) {
+			calls++
+			values := make([]string, len(fields))
+			for i, f := range fields {
+				values[i] = f.SubjectID + ":" + strings.ToUpper(f.Value)
+			}
+			return values, nil
+		}
+	}
+
+	tcs := []tc{
+		{
+			val: profiles()[:1],
+			want: []*Profile{
+				{
+					ID:    "abc",
+					Email: "*****************",
+					Devices: []Device{
+						{IPAddr: "***************"},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			val: profiles()[:1],
+			want: []*Profile{
+				{
+					ID:    "abc",
+					Email: "abc:EMAIL@EXAMPLE.COM",
+					Devices: []Device{
+						{IPAddr: "abc:169.251.207.194"},
+					},
+				},
+			},
+			option: bulkUpper,
+			calls:  1,
+			ok:     true,
+		},
+		{
+			val: []*Profile{profiles()[0], profiles()[2]},
+			want: []*Profile{
+				{
+					ID:    "abc",
+					Email: "abc:EMAIL@EXAMPLE.COM",
+					Devices: []Device{
+						{IPAddr: "abc:169.251.207.194"},
+					},
+				},
+				{
+					ID:       "def",
+					Fullname: "def:GUADALUPE KEMMER DDS",
+				},
+			},
+			option: bulkUpper,
+			calls:  1,
+			ok:     true,
+		},
+		{
+			val:    profiles(),
+			option: bulkUpper,
+			ok:     false,
+			err:    ErrUnsupportedType,
+		},
+		{
+			val: profiles()[:1],
+			option: func(rc *RedactConfig) {
+				rc.BulkRedactFunc = func(ctx context.Context, fields []FieldValue) ([]string, error) {
+					return nil, nil
+				}
+			},
+			ok:  false,
+			err: ErrBulkRedactMismatch,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			calls = 0
+			err := RedactMany(tc.val, tc.option)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, tc.val) {
+				t.Fatalf("want %+v, got %+v", tc.want, tc.val)
+			}
+			if calls != tc.calls {
+				t.Fatalf("expect bulk redact func be called %d times, got %d", tc.calls, calls)
+			}
+		})
+	}
+
+	// struct values are redacted in place
+	vals := []Profile{*profiles()[0]}
+	if err := RedactMany(vals, WithRegisteredMasks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want := Email("*****@example.com"); vals[0].Email != want {
+		t.Fatalf("want %s, got %s", want, vals[0].Email)
+	}
+}
@@ -0,0 +1,42 @@
+package sensitive
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// BytesReplaceFunc is a callback function executed by the [Struct.ReplaceBytes] method
+// for each sensitive data field of a bytes type, e.g. []byte, [json.RawMessage] or [database/sql.RawBytes].
+//
+// It receives the original content of the field and returns the new one.
+// Note that the given slice shares its underlying array with the field, and must not be modified in place.
+type BytesReplaceFunc func(fr FieldReplace, val []byte) ([]byte, error)
+
+// BytesReplaceFuncCtx is the context-aware variant of [BytesReplaceFunc] executed by the [Struct.ReplaceBytesContext] method.
+type BytesReplaceFuncCtx func(ctx context.Context, fr FieldReplace, val []byte) ([]byte, error)
+
+var (
+	jsonRawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// isBytesType reports whether the given type is a slice of bytes.
+func isBytesType(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Uint8
+}
+
+// IsJSON reports whether the sensitive value is raw JSON, i.e. a [json.RawMessage],
+// in which case its replacement must be valid JSON as well.
+func (fr FieldReplace) IsJSON() bool {
+	rt := fr.RType
+	if rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return rt == jsonRawMessageType
+}
+
+// RedactBytesDefaultFunc redacts bytes data fields by replacing them with nil,
+// which is encoded as `null` in the case of [json.RawMessage].
+func RedactBytesDefaultFunc(_ FieldReplace, _ []byte) ([]byte, error) {
+	return nil, nil
+}
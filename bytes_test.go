@@ -0,0 +1,244 @@
+package sensitive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRedact_Bytes(t *testing.T) {
+	type tc struct {
+		val    any
+		want   any
+		option func(*RedactConfig)
+		ok     bool
+		err    error
+	}
+
+	errTest := errors.New("test error")
+
+	tcs := []tc{
+		func() tc {
+			type T struct {
+				Avatar   []byte            `sensitive:"data"`
+				Payload  json.RawMessage   `sensitive:"data"`
+				Document *[]byte           `sensitive:"data"`
+				Files    [][]byte          `sensitive:"data"`
+				Notes    map[string][]byte `sensitive:"data"`
+				Email    string            `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Avatar:   []byte("avatar"),
+					Payload:  json.RawMessage(`{"ssn":"123-45-6789"}`),
+					Document: ptr([]byte("document")),
+					Files:    [][]byte{[]byte("file")},
+					Notes:    map[string][]byte{"a": []byte("note")},
+					Email:    "email",
+				},
+				want: &T{
+					Avatar:   nil,
+					Payload:  nil,
+					Document: ptr([]byte(nil)),
+					Files:    [][]byte{nil},
+					Notes:    map[string][]byte{"a": nil},
+					Email:    "*****",
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Avatar  []byte          `sensitive:"data"`
+				Payload json.RawMessage `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Avatar:  []byte("avatar"),
+					Payload: json.RawMessage(`{"ssn":"123-45-6789"}`),
+				},
+				want: &T{
+					Avatar:  []byte("******"),
+					Payload: json.RawMessage(`"REDACTED"`),
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactBytesFunc = func(fr FieldReplace, val []byte) ([]byte, error) {
+						if fr.IsJSON() {
+							return []byte(`"REDACTED"`), nil
+						}
+						return bytes.Repeat([]byte("*"), len(val)), nil
+					}
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Avatar []byte `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Avatar: []byte("avatar"),
+				},
+				want: &T{
+					Avatar: []byte("avatar"),
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactBytesFunc = nil
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Avatar []byte `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Avatar: []byte("avatar"),
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactBytesFunc = func(fr FieldReplace, val []byte) ([]byte, error) {
+						return nil, errTest
+					}
+				},
+				ok:  false,
+				err: errTest,
+			}
+		}(),
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			err := Redact(tc.val, tc.option)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, tc.val) {
+				t.Fatalf("want %+v, got %+v", tc.want, tc.val)
+			}
+		})
+	}
+}
+
+func TestRedact_Bytes_ContinueOnError(t *testing.T) {
+	type T struct {
+		Avatar []byte `sensitive:"data,kind=avatar"`
+	}
+
+	errTest := errors.New("test error")
+
+	val := &T{Avatar: []byte("avatar")}
+	err := Redact(val, func(rc *RedactConfig) {
+		rc.ContinueOnError = true
+		rc.RedactBytesFunc = func(fr FieldReplace, val []byte) ([]byte, error) {
+			return nil, errTest
+		}
+	})
+	var fErr *FieldError
+	if !errors.As(err, &fErr) || !errors.Is(err, errTest) {
+		t.Fatalf("expect err be a FieldError of %v, got %v", errTest, err)
+	}
+	if fErr.Path != "Avatar" || fErr.Kind != "avatar" {
+		t.Fatalf("unexpected field error %+v", fErr)
+	}
+	if val.Avatar != nil {
+		t.Fatalf("expect failing field be nil, got %s", val.Avatar)
+	}
+}
+
+func TestReplaceBytes(t *testing.T) {
+	type T struct {
+		Profile `sensitive:"dive"`
+		Avatar  []byte          `sensitive:"data,kind=avatar"`
+		Payload json.RawMessage `sensitive:"data"`
+	}
+
+	val := &T{
+		Profile: Profile{Email: "email@example.com"},
+		Avatar:  []byte("avatar"),
+		Payload: json.RawMessage(`{"a":1}`),
+	}
+
+	s, err := Scan(val, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	got := map[string]string{}
+	if err := s.ReplaceBytes(func(fr FieldReplace, val []byte) ([]byte, error) {
+		got[fr.Path] = string(val)
+		if fr.IsJSON() != (fr.Name == "Payload") {
+			t.Fatalf("unexpected IsJSON result for %s", fr.Path)
+		}
+		return append([]byte("x"), val...), nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	want := map[string]string{
+		"Avatar":  "avatar",
+		"Payload": `{"a":1}`,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	if string(val.Avatar) != "xavatar" || val.Email != "email@example.com" {
+		t.Fatalf("expect only bytes values be replaced, got %+v", val)
+	}
+
+	// string replace functions skip bytes values
+	if err := s.Replace(func(fr FieldReplace, val string) (string, error) {
+		if fr.Name != "Email" {
+			t.Fatalf("unexpected field %s", fr.Path)
+		}
+		return val, nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.ReplaceBytesContext(ctx, func(ctx context.Context, fr FieldReplace, val []byte) ([]byte, error) {
+		return val, nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect err is %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestPseudonymize_Bytes(t *testing.T) {
+	type T struct {
+		Email   string          `sensitive:"data"`
+		Avatar  []byte          `sensitive:"data"`
+		Payload json.RawMessage `sensitive:"data"`
+	}
+
+	val := &T{
+		Email:   "email@example.com",
+		Avatar:  []byte("email@example.com"),
+		Payload: json.RawMessage("email@example.com"),
+	}
+	if err := Redact(val, WithPseudonymization([]byte("secret"))); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if string(val.Avatar) != val.Email {
+		t.Fatalf("expect bytes pseudonym be %s, got %s", val.Email, val.Avatar)
+	}
+	var got string
+	if err := json.Unmarshal(val.Payload, &got); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if got != val.Email {
+		t.Fatalf("expect raw JSON pseudonym be %s, got %s", val.Email, got)
+	}
+}
@@ -4,12 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+
+	"github.com/ln80/struct-sensitive/internal/option"
 )
 
 // Check verifies whether the provided struct contains sensitive data fields.
 // It returns an error if the 'sensitive' tag is misconfigured or if the value parameter
 // is not a struct or a pointer to a struct.
-func Check(v any) (found bool, err error) {
+//
+// Unlike [Scan], Check is strict by default (see [ScanConfig.Strict]), so that it can be used
+// to validate the 'sensitive' tag configuration of a struct type, e.g. in unit tests.
+func Check(v any, opts ...func(*ScanConfig)) (found bool, err error) {
+	cfg := ScanConfig{
+		Strict: true,
+	}
+	option.Apply(&cfg, opts)
+
 	defer func() {
 		// normalize error
 		if err != nil && !errors.Is(err, ErrUnsupportedType) {
@@ -30,6 +40,11 @@ func Check(v any) (found bool, err error) {
 		return
 
 	}
+	if cfg.Strict {
+		if err = ssT.strictError(); err != nil {
+			return
+		}
+	}
 
 	found = ssT.hasSensitive
 	return
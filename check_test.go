@@ -3,14 +3,16 @@ package sensitive
 import (
 	"errors"
 	"strconv"
+	"strings"
 	"testing"
 )
 
 func TestCheck(t *testing.T) {
 	type tc struct {
-		val any
-		ok  bool
-		err error
+		val    any
+		option func(*ScanConfig)
+		ok     bool
+		err    error
 	}
 
 	tcs := []tc{
@@ -32,8 +34,26 @@ func TestCheck(t *testing.T) {
 			val: struct {
 				Val struct{} `sensitive:"data"`
 			}{Val: struct{}{}},
+			ok:  false,
+			err: ErrUnsupportedFieldType,
+		},
+		{
+			val: struct {
+				Val struct{} `sensitive:"data"`
+			}{Val: struct{}{}},
+			option: func(sc *ScanConfig) {
+				sc.Strict = false
+			},
 			ok: false,
 		},
+		{
+			val: struct {
+				Address
+				Roles []string `sensitive:"dive"`
+			}{},
+			ok:  false,
+			err: ErrUnsupportedFieldType,
+		},
 		{
 			val: Address{Street: "578 Abbott Viaduct"},
 			ok:  true,
@@ -52,7 +72,7 @@ func TestCheck(t *testing.T) {
 
 	for i, tc := range tcs {
 		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
-			ok, err := Check(tc.val)
+			ok, err := Check(tc.val, tc.option)
 			if got, want := err, tc.err; !errors.Is(got, want) {
 				t.Fatalf("want %v got %v", got, want)
 			}
@@ -63,3 +83,37 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestCheck_Strict(t *testing.T) {
+	type Nested struct {
+		Meta struct{} `sensitive:"data"`
+	}
+	type T struct {
+		Address  `sensitive:"dive"`
+		Nested   *Nested           `sensitive:"dive"`
+		Contacts map[string]string `sensitive:"dive,keys=email"`
+	}
+
+	_, err := Check(T{})
+	if !errors.Is(err, ErrUnsupportedFieldType) {
+		t.Fatalf("expect err is %v, got %v", ErrUnsupportedFieldType, err)
+	}
+	if want := "'Nested.Meta'"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("expect err contains the field path %s, got %v", want, err)
+	}
+
+	// Scan is not strict by default
+	if _, err := Scan(&T{}, false); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if _, err := Scan(&T{}, false, func(sc *ScanConfig) {
+		sc.Strict = true
+	}); !errors.Is(err, ErrUnsupportedFieldType) {
+		t.Fatalf("expect err is %v, got %v", ErrUnsupportedFieldType, err)
+	}
+	if err := Redact(&T{}, func(rc *RedactConfig) {
+		rc.Strict = true
+	}); !errors.Is(err, ErrUnsupportedFieldType) {
+		t.Fatalf("expect err is %v, got %v", ErrUnsupportedFieldType, err)
+	}
+}
@@ -0,0 +1,242 @@
+package sensitive
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// RedactCopy returns a redacted copy of the given struct and leaves the original value untouched.
+//
+// Only the sensitive paths (data fields and nested structs, pointers, slices and maps reached via `dive`)
+// are cloned; the remaining fields are shallow-copied and thus share their underlying values with the original.
+// The value field of nullable wrappers (see [RegisterWrapper]) is cloned as well, e.g. the pointer of a sql.Null[*string],
+// and so are the exported fields of [Redactable] and [Maskable] structs; their unexported fields are shallow-copied.
+//
+// It accepts the same options as [Redact].
+func RedactCopy[T any](v *T, opts ...func(*RedactConfig)) (*T, error) {
+	c, err := redactCopy(v, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*T), nil
+}
+
+// MaskCopy returns a masked copy of the given struct and leaves the original value untouched.
+//
+// It is simply a facade function that calls [RedactCopy] with [WithRegisteredMasks] option.
+func MaskCopy[T any](v *T, opts ...func(*RedactConfig)) (*T, error) {
+	return RedactCopy(v, append(opts, WithRegisteredMasks)...)
+}
+
+// redactCopy clones the given struct or struct pointer and redacts the clone.
+// It returns the redacted clone in the same form as the given value, i.e. a struct or a struct pointer.
+func redactCopy(v any, opts []func(*RedactConfig)) (any, error) {
+	c, err := copyStruct(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := Redact(c, opts...); err != nil {
+		return nil, err
+	}
+	if reflect.TypeOf(v).Kind() != reflect.Pointer {
+		return reflect.ValueOf(c).Elem().Interface(), nil
+	}
+	return c, nil
+}
+
+// copyStruct returns a pointer to a copy of the given struct or struct pointer,
+// in which the sensitive paths are deeply cloned.
+func copyStruct(v any) (any, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w '%v'", ErrUnsupportedType, rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w '%v'", ErrUnsupportedType, reflect.TypeOf(v))
+	}
+
+	ssType, err := scanStructType(rv.Type())
+	if err != nil {
+		return nil, errors.Join(ErrInvalidTagConfiguration, err)
+	}
+
+	c := cloner{memo: make(map[visitKey]reflect.Value)}
+	return c.cloneStruct(ssType, rv).Addr().Interface(), nil
+}
+
+// cloner deeply clones sensitive values.
+// It memoizes cloned references, so that shared references remain shared in the clone
+// and reference cycles do not cause an infinite recursion.
+type cloner struct {
+	memo map[visitKey]reflect.Value
+}
+
+// cloneStruct returns an addressable copy of the given struct value.
+// Sensitive fields are deeply cloned while the other ones are shallow-copied.
+func (c cloner) cloneStruct(ssType sensitiveStructType, src reflect.Value) reflect.Value {
+	dst := reflect.New(src.Type()).Elem()
+	dst.Set(src)
+	if !ssType.hasSensitive {
+		return dst
+	}
+	if src.CanAddr() {
+		// the struct might have already been registered as the element of a cloned pointer.
+		key, _ := refKey(src.Addr())
+		if _, ok := c.memo[key]; !ok {
+			c.memo[key] = dst.Addr()
+		}
+	}
+
+	for _, ssField := range ssType.sensitiveFields {
+		v := src.FieldByIndex(ssField.sf.Index)
+		if v.IsZero() {
+			continue
+		}
+
+		leaf := func(v reflect.Value) reflect.Value { return v }
+		switch {
+		case ssField.wrapper != nil:
+			w, isHook := *ssField.wrapper, ssField.isHook
+			leaf = func(v reflect.Value) reflect.Value { return c.cloneWrapper(w, v, isHook) }
+		case ssField.isHook:
+			leaf = c.cloneHook
+		case ssField.isDynamic:
+			leaf = c.cloneDynamic
+		case ssField.isNested:
+			cacheMu.Lock()
+			ssT := *ssField.getType(cache)
+			cacheMu.Unlock()
+			if !ssT.hasSensitive && !ssField.hasKeys {
+				continue
+			}
+			leaf = func(v reflect.Value) reflect.Value {
+				if v.Kind() != reflect.Struct {
+					return v
+				}
+				return c.cloneStruct(ssT, v)
+			}
+		}
+
+		f := dst.FieldByIndex(ssField.sf.Index)
+		if !f.CanSet() {
+			continue
+		}
+		f.Set(c.cloneValue(v, leaf))
+	}
+
+	return dst
+}
+
+// cloneDynamic returns a copy of the given interface value in which the held struct,
+// or struct pointer, is deeply cloned.
+func (c cloner) cloneDynamic(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Interface || v.IsNil() {
+		return v
+	}
+	ssT, _, ok, _ := dynamicStruct(v)
+	if !ok {
+		return v
+	}
+	dst := reflect.New(v.Type()).Elem()
+	dst.Set(c.cloneValue(v.Elem(), func(v reflect.Value) reflect.Value {
+		if v.Kind() != reflect.Struct {
+			return v
+		}
+		return c.cloneStruct(ssT, v)
+	}))
+	return dst
+}
+
+// cloneWrapper returns a copy of the given wrapper value in which the value field is deeply cloned,
+// since the wrapped value is replaced in place, e.g. through the pointer of a sql.Null[*string].
+// The isHook parameter indicates that the wrapped value implements [Redactable] or [Maskable].
+func (c cloner) cloneWrapper(w wrapper, v reflect.Value, isHook bool) reflect.Value {
+	if v.Kind() != reflect.Struct {
+		return v
+	}
+	dst := reflect.New(v.Type()).Elem()
+	dst.Set(v)
+	leaf := func(v reflect.Value) reflect.Value { return v }
+	if isHook {
+		leaf = c.cloneHook
+	}
+	f := dst.FieldByIndex(w.value)
+	f.Set(c.cloneValue(f, leaf))
+	return dst
+}
+
+// cloneHook returns a copy of the given [Redactable] or [Maskable] value in which the exported fields
+// are deeply cloned, since the value is redacted in place by its own methods, e.g. a `Money{Digits []byte}`.
+// Unexported fields are shallow-copied, as they can't be set.
+func (c cloner) cloneHook(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Struct {
+		return v
+	}
+	dst := reflect.New(v.Type()).Elem()
+	dst.Set(v)
+	for i := 0; i < dst.NumField(); i++ {
+		if f := dst.Field(i); f.CanSet() {
+			f.Set(c.cloneValue(v.Field(i), c.cloneHook))
+		}
+	}
+	return dst
+}
+
+// cloneValue returns a copy of the given value in which pointers, slices, arrays and maps are duplicated.
+// The remaining values are copied using the leaf function.
+func (c cloner) cloneValue(v reflect.Value, leaf func(reflect.Value) reflect.Value) reflect.Value {
+	key, isRef := refKey(v)
+	if isRef {
+		if cv, ok := c.memo[key]; ok {
+			return cv
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		cv := reflect.New(v.Type().Elem())
+		c.memo[key] = cv
+		cv.Elem().Set(c.cloneValue(v.Elem(), leaf))
+		return cv
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cv := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		c.memo[key] = cv
+		for i := 0; i < v.Len(); i++ {
+			cv.Index(i).Set(c.cloneValue(v.Index(i), leaf))
+		}
+		return cv
+
+	case reflect.Array:
+		cv := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cv.Index(i).Set(c.cloneValue(v.Index(i), leaf))
+		}
+		return cv
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cv := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.memo[key] = cv
+		iter := v.MapRange()
+		for iter.Next() {
+			cv.SetMapIndex(iter.Key(), c.cloneValue(iter.Value(), leaf))
+		}
+		return cv
+
+	default:
+		return leaf(v)
+	}
+}
@@ -0,0 +1,252 @@
+package sensitive
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRedactCopy(t *testing.T) {
+	type Meta struct {
+		Role string
+	}
+	type T struct {
+		Profile   `sensitive:"dive"`
+		Address   *Address           `sensitive:"dive"`
+		Addresses []*Address         `sensitive:"dive"`
+		Contacts  map[string]Address `sensitive:"dive,keys=email"`
+		Emails    []string           `sensitive:"data"`
+		Meta      *Meta
+	}
+
+	type tc struct {
+		val  func() *T
+		want *T
+		ok   bool
+		err  error
+	}
+
+	tcs := []tc{
+		{
+			val: func() *T { return nil },
+			ok:  false,
+			err: ErrUnsupportedType,
+		},
+		{
+			val: func() *T {
+				return &T{
+					Profile: Profile{
+						ID:    "abc",
+						Email: "email@example.com",
+						Phone: ptr("250-308-0529"),
+						Devices: []Device{
+							{IPAddr: "169.251.207.194"},
+						},
+					},
+					Address: &Address{Street: "07024 Quigley Trace"},
+					Addresses: []*Address{
+						{Street: "7234 Antone Springs"},
+						nil,
+					},
+					Contacts: map[string]Address{
+						"email@example.com": {Street: "90 Kerluke Pine"},
+					},
+					Emails: []string{"email@example.com"},
+					Meta:   &Meta{Role: "Teacher"},
+				}
+			},
+			want: &T{
+				Profile: Profile{
+					ID:    "abc",
+					Email: "*****************",
+					Phone: ptr("************"),
+					Devices: []Device{
+						{IPAddr: "***************"},
+					},
+				},
+				Address: &Address{Street: "*******************"},
+				Addresses: []*Address{
+					{Street: "*******************"},
+					nil,
+				},
+				Contacts: map[string]Address{
+					"*****************": {Street: "***************"},
+				},
+				Emails: []string{"*****************"},
+				Meta:   &Meta{Role: "Teacher"},
+			},
+			ok: true,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			val, original := tc.val(), tc.val()
+
+			got, err := RedactCopy(val)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+			if !reflect.DeepEqual(original, val) {
+				t.Fatalf("expect original value %+v be untouched, got %+v", original, val)
+			}
+			if got.Meta != val.Meta {
+				t.Fatal("expect non-sensitive sub-trees be shared")
+			}
+		})
+	}
+}
+
+func TestMaskCopy(t *testing.T) {
+	val := &Profile{
+		Email:    "email@example.com",
+		Fullname: "Guadalupe Kemmer DDS",
+		Devices: []Device{
+			{IPAddr: "169.251.207.194"},
+		},
+	}
+	want := &Profile{
+		Email:    "*****@example.com",
+		Fullname: "********************",
+		Devices: []Device{
+			{IPAddr: "169.251.207.***"},
+		},
+	}
+
+	got, err := MaskCopy(val)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+	if val.Email != "email@example.com" || val.Devices[0].IPAddr != "169.251.207.194" {
+		t.Fatalf("expect original value be untouched, got %+v", val)
+	}
+}
+
+func TestRedactCopy_Dynamic(t *testing.T) {
+	type T struct {
+		Payload any   `sensitive:"dive"`
+		Events  []any `sensitive:"dive"`
+	}
+
+	val := &T{
+		Payload: &Address{Street: "07024 Quigley Trace"},
+		Events: []any{
+			Profile{ID: "abc", Email: "email@example.com"},
+		},
+	}
+	want := &T{
+		Payload: &Address{Street: "*******************"},
+		Events: []any{
+			Profile{ID: "abc", Email: "*****************"},
+		},
+	}
+
+	got, err := RedactCopy(val, func(rc *RedactConfig) {
+		rc.RequireSubjectID = true
+	})
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+	if street := val.Payload.(*Address).Street; street != "07024 Quigley Trace" {
+		t.Fatalf("expect original value be untouched, got %s", street)
+	}
+	if email := val.Events[0].(Profile).Email; email != "email@example.com" {
+		t.Fatalf("expect original value be untouched, got %s", email)
+	}
+}
+
+func TestRedactCopy_Wrapper(t *testing.T) {
+	type T struct {
+		Name    sql.Null[*string]   `sensitive:"data"`
+		Aliases []sql.Null[*string] `sensitive:"data"`
+	}
+
+	val := &T{
+		Name:    sql.Null[*string]{V: ptr("Kenna"), Valid: true},
+		Aliases: []sql.Null[*string]{{V: ptr("Eric"), Valid: true}},
+	}
+
+	got, err := RedactCopy(val)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if *got.Name.V != "*****" || *got.Aliases[0].V != "****" {
+		t.Fatalf("expect wrapped values be redacted, got %s, %s", *got.Name.V, *got.Aliases[0].V)
+	}
+
+	// log values rely on the copy as well.
+	logged, ok := LogValue(val).Any().(*T)
+	if !ok {
+		t.Fatalf("expect log value be a %T", val)
+	}
+	if *logged.Name.V != "*****" {
+		t.Fatalf("expect logged value be masked, got %s", *logged.Name.V)
+	}
+
+	if *val.Name.V != "Kenna" || *val.Aliases[0].V != "Eric" {
+		t.Fatalf("expect original value be untouched, got %s, %s", *val.Name.V, *val.Aliases[0].V)
+	}
+}
+
+type digits struct {
+	Digits []byte
+	Cents  *int
+}
+
+func (d *digits) Redact(fr FieldReplace) error {
+	for i := range d.Digits {
+		d.Digits[i] = '*'
+	}
+	if d.Cents != nil {
+		*d.Cents = 0
+	}
+	return nil
+}
+
+func TestRedactCopy_Hook(t *testing.T) {
+	type T struct {
+		Balance  digits             `sensitive:"data"`
+		Balances map[string]*digits `sensitive:"data"`
+		Debt     sql.Null[digits]   `sensitive:"data"`
+	}
+
+	val := &T{
+		Balance:  digits{Digits: []byte("1250"), Cents: ptr(99)},
+		Balances: map[string]*digits{"EUR": {Digits: []byte("300")}},
+		Debt:     sql.Null[digits]{V: digits{Digits: []byte("42")}, Valid: true},
+	}
+	want := &T{
+		Balance:  digits{Digits: []byte("1250"), Cents: ptr(99)},
+		Balances: map[string]*digits{"EUR": {Digits: []byte("300")}},
+		Debt:     sql.Null[digits]{V: digits{Digits: []byte("42")}, Valid: true},
+	}
+
+	got, err := RedactCopy(val)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if string(got.Balance.Digits) != "****" || *got.Balance.Cents != 0 ||
+		string(got.Balances["EUR"].Digits) != "***" || string(got.Debt.V.Digits) != "**" {
+		t.Fatalf("expect hook values be redacted, got %+v", got)
+	}
+	if !reflect.DeepEqual(want, val) {
+		t.Fatalf("expect original value be untouched, want %+v, got %+v", want, val)
+	}
+}
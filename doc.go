@@ -2,13 +2,28 @@
 Package sensitive provides a set functions to handle sensitive fields in structs, including:
 
   - [Mask] partially redacts sensitive data while preserving the format of the data type (aka kind).
-    It uses a set of predefined masks (e.g 'email' 'ipv4_addr') and allows to register additional masks.
+    It uses a set of predefined masks (e.g 'email' 'ipv4_addr' 'phone') and allows to register additional masks.
 
   - [Redact] replaces sensitive field values with a redaction symbol ('*') by default.
     The behavior can be customized through optional parameters.
+    [RedactContext] passes a context to the redact function and aborts as soon as the context is done.
+    [RedactMany] redacts a slice of structs and allows to serve all their sensitive values with a single bulk call.
+
+  - [WithPseudonymization] is a [Redact] option that replaces sensitive data with deterministic keyed HMAC digests.
+
+  - [RedactCopy] and [MaskCopy] behave like [Redact] and [Mask] but return a redacted copy
+    of the struct and leave the original value untouched.
+
+  - [MarshalJSON] returns the JSON encoding of a struct in which sensitive fields are redacted.
+
+  - [LogValue] and [NewSlogHandler] integrate with [log/slog] and mask sensitive structs in structured logs.
 
   - [Scan] is a lower-level function that gives access to sensitive struct metadata and a fields replacer.
-    This can be used to implement more advanced features such as client-side encryption.
+    This can be used to implement more advanced features such as client-side encryption
+    (see the [github.com/ln80/struct-sensitive/encrypt] package).
+    [Inspect] is its read-only counterpart that also accepts struct values, to be used with [Struct.Walk],
+    which visits string and text values, or [Struct.WalkValues], which visits every data field with its typed value.
+    [Struct.Collect] and [Struct.Apply] allow to replace sensitive values in two phases.
 
   - [Check] determines whether a struct contains any sensitive data fields.
 
@@ -0,0 +1,322 @@
+/*
+Package encrypt implements client-side encryption of sensitive struct fields, aka crypto-shredding.
+
+Each sensitive data field is encrypted with AES-GCM using a data key that belongs to the subject
+to whom the data belongs (see the `subjectID` tag). Deleting the subject's key using [Forget]
+makes all of the subject's encrypted data unrecoverable, which helps to comply with
+the right to erasure (GDPR).
+
+Data keys are managed by a [KeyStore]. The package provides an in-memory implementation
+for testing purposes and a file-backed one that wraps data keys with a master key.
+Implement the [KeyStore] interface to rely on a key management service instead.
+
+The [WithFormatPreserving] option enables format-preserving encryption for the kinds of data
+that downstream systems validate (e.g. `email`, `ipv4_addr`); see the [github.com/ln80/struct-sensitive/fpe] package.
+
+	type Profile struct {
+		ID    string `sensitive:"subjectID"`
+		Email string `sensitive:"data,kind=email"`
+	}
+
+	ks := encrypt.NewMemoryKeyStore()
+
+	p := Profile{ID: "abc", Email: "eric.prosacco@example.com"}
+
+	_ = encrypt.Encrypt(&p, ks) // p.Email is now encrypted
+	_ = encrypt.Decrypt(&p, ks) // p.Email is back in cleartext
+
+	_ = encrypt.Forget(ks, "abc") // p.Email can't be decrypted anymore
+*/
+package encrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sensitive "github.com/ln80/struct-sensitive"
+	"github.com/ln80/struct-sensitive/fpe"
+	"github.com/ln80/struct-sensitive/internal/option"
+)
+
+var (
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// versionSize is the size of the key version header prepended to ciphertexts.
+const versionSize = 4
+
+// fpeKeyVersion is the version of the data key used by format-preserving encryption.
+// Format-preserving ciphertexts can't embed the key version, hence they are bound to the first one.
+const fpeKeyVersion = 1
+
+// Config presents the configuration of the encryption and decryption functions.
+type Config struct {
+	// FormatPreserving enables format-preserving encryption (FF1) of the fields whose kind
+	// has a registered format (see [fpe.Register]). The other fields are encrypted using AES-GCM.
+	//
+	// It must be enabled for both encryption and decryption. This config is disabled by default.
+	//
+	// Note that format-preserving encryption is deterministic and always relies on the first version
+	// of the subject's data key, regardless of key rotation.
+	//
+	// Values that don't fit the format, e.g. an invalid email, or whose variable part is too short for FF1
+	// (see [fpe.FF1.MinLen]), e.g. an email with less than 4 alphanumeric characters in its local part,
+	// are encrypted using AES-GCM instead.
+	FormatPreserving bool
+}
+
+// WithFormatPreserving returns an option that enables format-preserving encryption.
+func WithFormatPreserving(c *Config) {
+	c.FormatPreserving = true
+}
+
+// Encrypt encrypts the sensitive data fields of the given struct pointer in place.
+//
+// The latest version of the data key is looked up (or created) in the key store using
+// the resolved subject ID of the struct. It returns an error if the struct does not have a subject ID.
+//
+// Encrypted values are base64-encoded, embed the key version, and are authenticated against the subject ID.
+// Bytes data fields (e.g. []byte) hold the raw ciphertext instead, except for raw JSON fields,
+// which hold the base64-encoded ciphertext as a JSON string.
+//
+// Only string and bytes data fields can be encrypted. It returns [sensitive.ErrUnsupportedFieldType]
+// before encrypting anything if the struct has other data fields, e.g. numbers or text types such as [net/netip.Addr].
+func Encrypt(structPtr any, ks KeyStore, opts ...func(*Config)) error {
+	return EncryptContext(context.Background(), structPtr, ks, opts...)
+}
+
+// EncryptContext is like [Encrypt] but passes the given context to the key store.
+func EncryptContext(ctx context.Context, structPtr any, ks KeyStore, opts ...func(*Config)) error {
+	cfg := Config{}
+	option.Apply(&cfg, opts)
+
+	accessor, err := sensitive.Scan(structPtr, true)
+	if err != nil {
+		return err
+	}
+	if !accessor.HasSensitive() {
+		return nil
+	}
+	if err := checkFields(accessor); err != nil {
+		return err
+	}
+
+	key, err := ks.GetOrCreateKey(ctx, accessor.SubjectID())
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(key.Material)
+	if err != nil {
+		return err
+	}
+
+	var fpeKey []byte
+	if err := accessor.ReplaceContext(ctx, func(ctx context.Context, fr sensitive.FieldReplace, val string) (string, error) {
+		if cfg.FormatPreserving {
+			if _, ok := fpe.Of(fr.Kind); ok {
+				if fpeKey == nil {
+					k, err := getFPEKey(ctx, ks, fr.SubjectID, key)
+					if err != nil {
+						return "", err
+					}
+					fpeKey = k
+				}
+				if ciphertext, err := fpe.Encrypt(fpeKey, []byte(fr.Kind), fr.Kind, val); err == nil {
+					return ciphertext, nil
+				}
+				// the value does not fit the format or is too short for FF1, fall back to AES-GCM.
+			}
+		}
+		ciphertext, err := seal(aead, key.Version, fr.SubjectID, []byte(val))
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(ciphertext), nil
+	}); err != nil {
+		return err
+	}
+
+	return accessor.ReplaceBytesContext(ctx, func(ctx context.Context, fr sensitive.FieldReplace, val []byte) ([]byte, error) {
+		ciphertext, err := seal(aead, key.Version, fr.SubjectID, val)
+		if err != nil {
+			return nil, err
+		}
+		if fr.IsJSON() {
+			return json.Marshal(ciphertext)
+		}
+		return ciphertext, nil
+	})
+}
+
+// Decrypt decrypts the sensitive data fields of the given struct pointer in place.
+//
+// It returns [ErrKeyNotFound] if the subject's data key does not exist, e.g. if the subject has been forgotten,
+// and [ErrInvalidCiphertext] if a sensitive field value is not a valid ciphertext.
+// Like [Encrypt], it returns [sensitive.ErrUnsupportedFieldType] if the struct has data fields that can't be encrypted.
+func Decrypt(structPtr any, ks KeyStore, opts ...func(*Config)) error {
+	return DecryptContext(context.Background(), structPtr, ks, opts...)
+}
+
+// DecryptContext is like [Decrypt] but passes the given context to the key store.
+func DecryptContext(ctx context.Context, structPtr any, ks KeyStore, opts ...func(*Config)) error {
+	cfg := Config{}
+	option.Apply(&cfg, opts)
+
+	accessor, err := sensitive.Scan(structPtr, true)
+	if err != nil {
+		return err
+	}
+	if !accessor.HasSensitive() {
+		return nil
+	}
+	if err := checkFields(accessor); err != nil {
+		return err
+	}
+
+	// Fields might be encrypted with different versions of the data key.
+	aeads := make(map[uint32]cipher.AEAD)
+	decrypt := func(ctx context.Context, subjectID string, ciphertext []byte) ([]byte, error) {
+		if len(ciphertext) < versionSize {
+			return nil, ErrInvalidCiphertext
+		}
+
+		version := binary.BigEndian.Uint32(ciphertext)
+		aead, ok := aeads[version]
+		if !ok {
+			key, err := ks.GetKey(ctx, subjectID, version)
+			if err != nil {
+				return nil, err
+			}
+			if aead, err = newAEAD(key.Material); err != nil {
+				return nil, err
+			}
+			aeads[version] = aead
+		}
+
+		return open(aead, subjectID, ciphertext)
+	}
+
+	var fpeKey []byte
+	if err := accessor.ReplaceContext(ctx, func(ctx context.Context, fr sensitive.FieldReplace, val string) (string, error) {
+		if cfg.FormatPreserving {
+			if _, ok := fpe.Of(fr.Kind); ok {
+				if fpeKey == nil {
+					key, err := ks.GetKey(ctx, fr.SubjectID, fpeKeyVersion)
+					if err != nil {
+						return "", err
+					}
+					fpeKey = deriveFPEKey(key)
+				}
+				// values encrypted using AES-GCM, i.e. the ones that don't fit the format or FF1, don't have the format of the kind.
+				if plaintext, err := fpe.Decrypt(fpeKey, []byte(fr.Kind), fr.Kind, val); err == nil {
+					return plaintext, nil
+				}
+			}
+		}
+
+		ciphertext, err := base64.StdEncoding.DecodeString(val)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+		}
+		plaintext, err := decrypt(ctx, fr.SubjectID, ciphertext)
+		if err != nil {
+			return "", err
+		}
+		return string(plaintext), nil
+	}); err != nil {
+		return err
+	}
+
+	return accessor.ReplaceBytesContext(ctx, func(ctx context.Context, fr sensitive.FieldReplace, val []byte) ([]byte, error) {
+		ciphertext := val
+		if fr.IsJSON() {
+			if err := json.Unmarshal(val, &ciphertext); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+			}
+		}
+		return decrypt(ctx, fr.SubjectID, ciphertext)
+	})
+}
+
+// Forget deletes the data key of the given subject.
+// As a result, all of the subject's encrypted data becomes unrecoverable.
+func Forget(ks KeyStore, subjectID string) error {
+	return ForgetContext(context.Background(), ks, subjectID)
+}
+
+// ForgetContext is like [Forget] but passes the given context to the key store.
+func ForgetContext(ctx context.Context, ks KeyStore, subjectID string) error {
+	return ks.DeleteKey(ctx, subjectID)
+}
+
+// checkFields returns an error if the sensitive data fields of the struct are not all strings or bytes,
+// so that the struct is never left partially encrypted.
+func checkFields(accessor sensitive.Struct) error {
+	return accessor.WalkValues(func(fr sensitive.FieldReplace, val any) error {
+		switch val.(type) {
+		case string, []byte:
+			return nil
+		}
+		return fmt.Errorf("%w: field '%s' of type '%v' can't be encrypted", sensitive.ErrUnsupportedFieldType, fr.Path, fr.RType)
+	})
+}
+
+// getFPEKey returns the format-preserving encryption key of the given subject.
+// It is derived from the first version of the data key, given the latest one.
+func getFPEKey(ctx context.Context, ks KeyStore, subjectID string, latest Key) ([]byte, error) {
+	key := latest
+	if key.Version != fpeKeyVersion {
+		var err error
+		if key, err = ks.GetKey(ctx, subjectID, fpeKeyVersion); err != nil {
+			return nil, err
+		}
+	}
+	return deriveFPEKey(key), nil
+}
+
+// deriveFPEKey derives a dedicated key for format-preserving encryption
+// so that the data key is not used by different algorithms.
+func deriveFPEKey(key Key) []byte {
+	mac := hmac.New(sha256.New, key.Material)
+	mac.Write([]byte("fpe"))
+	return mac.Sum(nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, version uint32, subjectID string, plaintext []byte) ([]byte, error) {
+	header := make([]byte, versionSize+aead.NonceSize(), versionSize+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	binary.BigEndian.PutUint32(header, version)
+	nonce := header[versionSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plaintext, append([]byte(subjectID), header[:versionSize]...)), nil
+}
+
+func open(aead cipher.AEAD, subjectID string, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < versionSize+aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	version, nonce, ciphertext := ciphertext[:versionSize], ciphertext[versionSize:versionSize+aead.NonceSize()], ciphertext[versionSize+aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, append([]byte(subjectID), version...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+	return plaintext, nil
+}
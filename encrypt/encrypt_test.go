@@ -0,0 +1,408 @@
+package encrypt_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	sensitive "github.com/ln80/struct-sensitive"
+	"github.com/ln80/struct-sensitive/encrypt"
+)
+
+type Device struct {
+	IPAddr string `sensitive:"data,kind=ipv4_addr"`
+}
+
+type Profile struct {
+	ID       string            `sensitive:"subjectID"`
+	Email    string            `sensitive:"data,kind=email"`
+	Phone    *string           `sensitive:"data"`
+	Aliases  []string          `sensitive:"data"`
+	Devices  map[string]Device `sensitive:"dive"`
+	Fullname string            `sensitive:"data"`
+	Role     string
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
+
+func TestEncrypt(t *testing.T) {
+	type tc struct {
+		val  func() any
+		ok   bool
+		err  error
+		same bool
+	}
+
+	tcs := []tc{
+		{
+			val: func() any { return Profile{ID: "abc"} },
+			ok:  false,
+			err: sensitive.ErrUnsupportedType,
+		},
+		{
+			val: func() any { return &Profile{Email: "email@example.com"} },
+			ok:  false,
+			err: sensitive.ErrSubjectIDNotFound,
+		},
+		{
+			val: func() any {
+				return &struct{ Email string }{Email: "email@example.com"}
+			},
+			ok:   true,
+			same: true,
+		},
+		{
+			val: func() any {
+				return &Profile{
+					ID:      "abc",
+					Email:   "email@example.com",
+					Phone:   ptr("250-308-0529"),
+					Aliases: []string{"Kenna", "Kenna31"},
+					Devices: map[string]Device{
+						"home": {IPAddr: "169.251.207.194"},
+					},
+					Role: "Teacher",
+				}
+			},
+			ok: true,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			ks := encrypt.NewMemoryKeyStore()
+
+			val, original := tc.val(), tc.val()
+			err := encrypt.Encrypt(val, ks)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if got := reflect.DeepEqual(original, val); got != tc.same {
+				t.Fatalf("expect encrypted value %+v be equal to the original one: %v, got %v", val, tc.same, got)
+			}
+
+			if err := encrypt.Decrypt(val, ks); err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(original, val) {
+				t.Fatalf("want %+v, got %+v", original, val)
+			}
+		})
+	}
+}
+
+func TestEncrypt_Forget(t *testing.T) {
+	ks := encrypt.NewMemoryKeyStore()
+
+	p := &Profile{
+		ID:    "abc",
+		Email: "email@example.com",
+		Role:  "Teacher",
+	}
+	if err := encrypt.Encrypt(p, ks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if p.Email == "email@example.com" {
+		t.Fatal("expect email be encrypted")
+	}
+	if p.Role != "Teacher" || p.ID != "abc" {
+		t.Fatalf("expect non-sensitive fields be untouched, got %+v", p)
+	}
+
+	// data of another subject can't be decrypted with a different key.
+	other := &Profile{ID: "def", Email: p.Email}
+	if _, err := ks.GetOrCreateKey(context.Background(), "def"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := encrypt.Decrypt(other, ks); !errors.Is(err, encrypt.ErrInvalidCiphertext) {
+		t.Fatalf("expect err is %v, got %v", encrypt.ErrInvalidCiphertext, err)
+	}
+
+	if err := encrypt.Forget(ks, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := encrypt.Decrypt(p, ks); !errors.Is(err, encrypt.ErrKeyNotFound) {
+		t.Fatalf("expect err is %v, got %v", encrypt.ErrKeyNotFound, err)
+	}
+}
+
+func TestDecrypt_InvalidCiphertext(t *testing.T) {
+	ks := encrypt.NewMemoryKeyStore()
+	if _, err := ks.GetOrCreateKey(context.Background(), "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	for i, val := range []string{"not base64 !", "YWJj", "AAAAAWFiY2RlZmdoaWprbA=="} {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			p := &Profile{ID: "abc", Email: val}
+			if err := encrypt.Decrypt(p, ks); !errors.Is(err, encrypt.ErrInvalidCiphertext) {
+				t.Fatalf("expect err is %v, got %v", encrypt.ErrInvalidCiphertext, err)
+			}
+		})
+	}
+}
+
+func TestDecrypt_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	ks := encrypt.NewMemoryKeyStore()
+
+	p := &Profile{ID: "abc", Email: "email@example.com"}
+	if err := encrypt.EncryptContext(ctx, p, ks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	if _, err := ks.RotateKey(ctx, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	// encrypt another field with the new key version
+	p.Fullname = "Guadalupe Kemmer DDS"
+	p2 := &Profile{ID: "abc", Fullname: p.Fullname}
+	if err := encrypt.EncryptContext(ctx, p2, ks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	p.Fullname = p2.Fullname
+
+	if err := encrypt.DecryptContext(ctx, p, ks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	want := &Profile{ID: "abc", Email: "email@example.com", Fullname: "Guadalupe Kemmer DDS"}
+	if !reflect.DeepEqual(want, p) {
+		t.Fatalf("want %+v, got %+v", want, p)
+	}
+
+	if err := encrypt.ForgetContext(ctx, ks, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := encrypt.DecryptContext(ctx, p2, ks); !errors.Is(err, encrypt.ErrKeyNotFound) {
+		t.Fatalf("expect err is %v, got %v", encrypt.ErrKeyNotFound, err)
+	}
+}
+
+func TestEncrypt_FormatPreserving(t *testing.T) {
+	ctx := context.Background()
+	ks := encrypt.NewMemoryKeyStore()
+
+	newProfile := func() *Profile {
+		return &Profile{
+			ID:    "abc",
+			Email: "eric.prosacco@example.com",
+			Devices: map[string]Device{
+				"home": {IPAddr: "169.251.207.194"},
+			},
+			Fullname: "Eric Prosacco",
+		}
+	}
+
+	p := newProfile()
+	if err := encrypt.EncryptContext(ctx, p, ks, encrypt.WithFormatPreserving); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if p.Email == "eric.prosacco@example.com" || !regexp.MustCompile(`^[0-9A-Za-z]{4}\.[0-9A-Za-z]{8}@example\.com$`).MatchString(p.Email) {
+		t.Fatalf("expect email be encrypted while preserving its format, got %s", p.Email)
+	}
+	if ip := p.Devices["home"].IPAddr; ip == "169.251.207.194" || !regexp.MustCompile(`^169(\.[0-9]{1,3}){3}$`).MatchString(ip) {
+		t.Fatalf("expect IP address be encrypted while preserving its format, got %s", ip)
+	}
+	if p.Fullname == "Eric Prosacco" || regexp.MustCompile(`^[A-Za-z ]+$`).MatchString(p.Fullname) {
+		t.Fatalf("expect fields without format be encrypted using AES-GCM, got %s", p.Fullname)
+	}
+
+	// format-preserving encryption survives key rotation.
+	if _, err := ks.RotateKey(ctx, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	p2 := newProfile()
+	if err := encrypt.EncryptContext(ctx, p2, ks, encrypt.WithFormatPreserving); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if p.Email != p2.Email {
+		t.Fatalf("expect %s, %s be equals", p.Email, p2.Email)
+	}
+
+	if err := encrypt.DecryptContext(ctx, p, ks, encrypt.WithFormatPreserving); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want := newProfile(); !reflect.DeepEqual(want, p) {
+		t.Fatalf("want %+v, got %+v", want, p)
+	}
+
+	// format-preserving ciphertexts can't be decrypted without the option.
+	if err := encrypt.DecryptContext(ctx, p2, ks); !errors.Is(err, encrypt.ErrInvalidCiphertext) {
+		t.Fatalf("expect err is %v, got %v", encrypt.ErrInvalidCiphertext, err)
+	}
+}
+
+func TestEncrypt_Bytes(t *testing.T) {
+	type Document struct {
+		ID      string          `sensitive:"subjectID"`
+		Scan    []byte          `sensitive:"data"`
+		Payload json.RawMessage `sensitive:"data"`
+		Pages   [][]byte        `sensitive:"data"`
+	}
+
+	ks := encrypt.NewMemoryKeyStore()
+
+	newDocument := func() *Document {
+		return &Document{
+			ID:      "abc",
+			Scan:    []byte("scan"),
+			Payload: json.RawMessage(`{"ssn":"123-45-6789"}`),
+			Pages:   [][]byte{[]byte("page")},
+		}
+	}
+
+	d := newDocument()
+	if err := encrypt.Encrypt(d, ks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if bytes.Equal(d.Scan, []byte("scan")) || bytes.Equal(d.Pages[0], []byte("page")) {
+		t.Fatalf("expect bytes be encrypted, got %+v", d)
+	}
+	if !json.Valid(d.Payload) || d.Payload[0] != '"' {
+		t.Fatalf("expect raw JSON be encrypted to a JSON string, got %s", d.Payload)
+	}
+
+	if err := encrypt.Decrypt(d, ks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want := newDocument(); !reflect.DeepEqual(want, d) {
+		t.Fatalf("want %+v, got %+v", want, d)
+	}
+
+	if err := encrypt.Encrypt(d, ks); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	d.Payload = json.RawMessage(`{"ssn":"123-45-6789"}`)
+	if err := encrypt.Decrypt(d, ks); !errors.Is(err, encrypt.ErrInvalidCiphertext) {
+		t.Fatalf("expect err is %v, got %v", encrypt.ErrInvalidCiphertext, err)
+	}
+}
+
+func TestEncrypt_FormatPreserving_Fallback(t *testing.T) {
+	type tc struct {
+		val func() *Profile
+	}
+
+	tcs := []tc{
+		// the local part is too short for FF1, hence it is encrypted using AES-GCM.
+		{
+			val: func() *Profile {
+				return &Profile{ID: "abc", Email: "bob@b.com", Fullname: "Bob"}
+			},
+		},
+		// values that don't fit the format are encrypted using AES-GCM as well.
+		{
+			val: func() *Profile {
+				return &Profile{
+					ID:       "abc",
+					Email:    "not-an-email",
+					Fullname: "Bob",
+					Devices:  map[string]Device{"a": {IPAddr: "localhost"}},
+				}
+			},
+		},
+	}
+
+	ks := encrypt.NewMemoryKeyStore()
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			p := tc.val()
+			if err := encrypt.Encrypt(p, ks, encrypt.WithFormatPreserving); err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			want := tc.val()
+			if p.Email == want.Email || p.Fullname == want.Fullname {
+				t.Fatalf("expect fields be encrypted, got %+v", p)
+			}
+			for k, d := range p.Devices {
+				if d.IPAddr == want.Devices[k].IPAddr {
+					t.Fatalf("expect fields be encrypted, got %+v", p)
+				}
+			}
+
+			if err := encrypt.Decrypt(p, ks, encrypt.WithFormatPreserving); err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(want, p) {
+				t.Fatalf("want %+v, got %+v", want, p)
+			}
+		})
+	}
+}
+
+func TestEncrypt_UnsupportedField(t *testing.T) {
+	type tc struct {
+		val  func() any
+		want any
+	}
+
+	type Patient struct {
+		ID    string `sensitive:"subjectID"`
+		Email string `sensitive:"data"`
+		Age   int    `sensitive:"data"`
+		Notes string `sensitive:"data"`
+	}
+	type Account struct {
+		ID      string             `sensitive:"subjectID"`
+		Email   string             `sensitive:"data"`
+		Balance map[string]float64 `sensitive:"data"`
+	}
+	type Session struct {
+		ID     string     `sensitive:"subjectID"`
+		Email  string     `sensitive:"data"`
+		IPAddr netip.Addr `sensitive:"data"`
+		Agent  string     `sensitive:"data"`
+	}
+
+	tcs := []tc{
+		{
+			val:  func() any { return &Patient{ID: "abc", Email: "email@example.com", Age: 36, Notes: "notes"} },
+			want: &Patient{ID: "abc", Email: "email@example.com", Age: 36, Notes: "notes"},
+		},
+		{
+			val: func() any {
+				return &Account{ID: "abc", Email: "email@example.com", Balance: map[string]float64{"EUR": 1.5}}
+			},
+			want: &Account{ID: "abc", Email: "email@example.com", Balance: map[string]float64{"EUR": 1.5}},
+		},
+		{
+			val: func() any {
+				return &Session{ID: "abc", Email: "email@example.com", IPAddr: netip.MustParseAddr("169.251.207.194"), Agent: "curl"}
+			},
+			want: &Session{ID: "abc", Email: "email@example.com", IPAddr: netip.MustParseAddr("169.251.207.194"), Agent: "curl"},
+		},
+	}
+
+	ks := encrypt.NewMemoryKeyStore()
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			for _, fn := range []func(any, encrypt.KeyStore, ...func(*encrypt.Config)) error{encrypt.Encrypt, encrypt.Decrypt} {
+				val := tc.val()
+				if err := fn(val, ks); !errors.Is(err, sensitive.ErrUnsupportedFieldType) {
+					t.Fatalf("expect err is %v, got %v", sensitive.ErrUnsupportedFieldType, err)
+				}
+				// the struct is rejected as a whole rather than partially encrypted.
+				if !reflect.DeepEqual(tc.want, val) {
+					t.Fatalf("want %+v, got %+v", tc.want, val)
+				}
+			}
+		})
+	}
+}
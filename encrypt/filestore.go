@@ -0,0 +1,201 @@
+package encrypt
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	ErrInvalidMasterKey = errors.New("invalid master key")
+)
+
+// FileKeyStore is a local, file-backed implementation of [KeyStore].
+//
+// It relies on envelope encryption: data keys are wrapped (encrypted) with a master key
+// before being persisted, hence the file content is useless without the master key.
+//
+// Note that deleting a key rewrites the file without it; however, the underlying storage
+// might still retain previous copies of the file.
+type FileKeyStore struct {
+	path string
+	aead cipher.AEAD
+
+	// keys holds the wrapped data keys indexed by subject ID, ordered by version.
+	keys map[string][][]byte
+	mu   sync.RWMutex
+}
+
+var _ KeyStore = &FileKeyStore{}
+
+// NewFileKeyStore opens the key store persisted at the given path, or creates it if not found.
+//
+// The master key must be an AES key of 16, 24 or 32 bytes. It returns [ErrInvalidMasterKey]
+// if the master key is invalid or does not match the one used to wrap the persisted keys.
+func NewFileKeyStore(path string, masterKey []byte) (*FileKeyStore, error) {
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMasterKey, err)
+	}
+
+	s := &FileKeyStore{
+		path: path,
+		aead: aead,
+		keys: make(map[string][][]byte),
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.keys); err != nil {
+		return nil, fmt.Errorf("invalid key store file '%s': %w", path, err)
+	}
+
+	// Fail early if the master key doesn't match.
+	for subjectID, keys := range s.keys {
+		if len(keys) == 0 {
+			continue
+		}
+		if _, err := s.unwrap(subjectID, 1, keys[0]); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return s, nil
+}
+
+// GetOrCreateKey implements KeyStore.
+func (s *FileKeyStore) GetOrCreateKey(_ context.Context, subjectID string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keys := s.keys[subjectID]; len(keys) > 0 {
+		version := uint32(len(keys))
+		return s.unwrap(subjectID, version, keys[version-1])
+	}
+	return s.rotate(subjectID)
+}
+
+// GetKey implements KeyStore.
+func (s *FileKeyStore) GetKey(_ context.Context, subjectID string, version uint32) (Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := s.keys[subjectID]
+	if version == 0 || int(version) > len(keys) {
+		return Key{}, ErrKeyNotFound
+	}
+	return s.unwrap(subjectID, version, keys[version-1])
+}
+
+// DeleteKey implements KeyStore.
+func (s *FileKeyStore) DeleteKey(_ context.Context, subjectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys, ok := s.keys[subjectID]
+	if !ok {
+		return nil
+	}
+	delete(s.keys, subjectID)
+	if err := s.persist(); err != nil {
+		s.keys[subjectID] = keys
+		return err
+	}
+	return nil
+}
+
+// RotateKey creates a new version of the data key of the given subject and returns it.
+// Previous versions are kept so that existing data can still be decrypted.
+func (s *FileKeyStore) RotateKey(_ context.Context, subjectID string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rotate(subjectID)
+}
+
+func (s *FileKeyStore) rotate(subjectID string) (Key, error) {
+	keys := s.keys[subjectID]
+	key, err := newKey(uint32(len(keys) + 1))
+	if err != nil {
+		return Key{}, err
+	}
+	wrapped, err := s.wrap(subjectID, key)
+	if err != nil {
+		return Key{}, err
+	}
+
+	s.keys[subjectID] = append(keys, wrapped)
+	if err := s.persist(); err != nil {
+		s.keys[subjectID] = keys
+		if len(keys) == 0 {
+			delete(s.keys, subjectID)
+		}
+		return Key{}, err
+	}
+	return key, nil
+}
+
+// persist atomically writes the wrapped keys to the store file.
+func (s *FileKeyStore) persist() error {
+	b, err := json.Marshal(s.keys)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// wrapAAD binds a wrapped key to its subject and version.
+func wrapAAD(subjectID string, version uint32) []byte {
+	return binary.BigEndian.AppendUint32([]byte(subjectID), version)
+}
+
+func (s *FileKeyStore) wrap(subjectID string, key Key) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(key.Material)+s.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, key.Material, wrapAAD(subjectID, key.Version)), nil
+}
+
+func (s *FileKeyStore) unwrap(subjectID string, version uint32, wrapped []byte) (Key, error) {
+	if len(wrapped) < s.aead.NonceSize() {
+		return Key{}, ErrInvalidMasterKey
+	}
+	nonce, ciphertext := wrapped[:s.aead.NonceSize()], wrapped[s.aead.NonceSize():]
+	material, err := s.aead.Open(nil, nonce, ciphertext, wrapAAD(subjectID, version))
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %v", ErrInvalidMasterKey, err)
+	}
+	return Key{Version: version, Material: material}, nil
+}
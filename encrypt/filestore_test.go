@@ -0,0 +1,88 @@
+package encrypt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileKeyStore(t *testing.T) {
+	masterKey := bytes.Repeat([]byte("k"), 32)
+
+	ks, err := NewFileKeyStore(filepath.Join(t.TempDir(), "keys.json"), masterKey)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	testKeyStore(t, ks)
+}
+
+func TestFileKeyStore_Persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	masterKey := bytes.Repeat([]byte("k"), 32)
+
+	if _, err := NewFileKeyStore(path, []byte("short")); !errors.Is(err, ErrInvalidMasterKey) {
+		t.Fatalf("expect err is %v, got %v", ErrInvalidMasterKey, err)
+	}
+
+	ks, err := NewFileKeyStore(path, masterKey)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	key, err := ks.GetOrCreateKey(ctx, "abc")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if _, err := ks.GetOrCreateKey(ctx, "def"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if bytes.Contains(b, key.Material) {
+		t.Fatal("expect data keys be wrapped")
+	}
+
+	// the persisted keys can't be used with a different master key
+	if _, err := NewFileKeyStore(path, bytes.Repeat([]byte("x"), 32)); !errors.Is(err, ErrInvalidMasterKey) {
+		t.Fatalf("expect err is %v, got %v", ErrInvalidMasterKey, err)
+	}
+
+	reopened, err := NewFileKeyStore(path, masterKey)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	got, err := reopened.GetKey(ctx, "abc", key.Version)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if !bytes.Equal(key.Material, got.Material) {
+		t.Fatal("expect the persisted key be returned")
+	}
+
+	if err := reopened.DeleteKey(ctx, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	reopened, err = NewFileKeyStore(path, masterKey)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if _, err := reopened.GetKey(ctx, "abc", key.Version); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expect err is %v, got %v", ErrKeyNotFound, err)
+	}
+	if _, err := reopened.GetKey(ctx, "def", 1); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	if err := os.WriteFile(path, []byte("invalid"), 0o600); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if _, err := NewFileKeyStore(path, masterKey); err == nil {
+		t.Fatal("expect err not to be nil")
+	}
+}
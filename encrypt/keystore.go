@@ -0,0 +1,119 @@
+package encrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrKeyNotFound = errors.New("encryption key not found")
+)
+
+// KeySize is the size in bytes of the generated data keys (AES-256).
+const KeySize = 32
+
+// Key is a versioned data key that belongs to a subject.
+type Key struct {
+	// Version is the version of the key, starting from 1.
+	// A new version is created each time the key is rotated.
+	Version uint32
+
+	// Material is the raw key material.
+	Material []byte
+}
+
+// KeyStore manages the data keys used to encrypt sensitive data, one versioned key per subject.
+//
+// The interface is deliberately narrow so it can be backed by any key management service.
+type KeyStore interface {
+	// GetOrCreateKey returns the latest version of the data key of the given subject,
+	// and creates it if not found.
+	GetOrCreateKey(ctx context.Context, subjectID string) (Key, error)
+
+	// GetKey returns the given version of the data key of the given subject.
+	// It returns [ErrKeyNotFound] if the key or the version does not exist.
+	GetKey(ctx context.Context, subjectID string, version uint32) (Key, error)
+
+	// DeleteKey deletes all the versions of the data key of the given subject.
+	// It does not fail if the key does not exist.
+	DeleteKey(ctx context.Context, subjectID string) error
+}
+
+// MemoryKeyStore is an in-memory implementation of [KeyStore].
+// It is mainly intended for testing purposes.
+type MemoryKeyStore struct {
+	keys map[string][]Key
+	mu   sync.RWMutex
+}
+
+var _ KeyStore = &MemoryKeyStore{}
+
+// NewMemoryKeyStore returns an empty in-memory key store.
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{
+		keys: make(map[string][]Key),
+	}
+}
+
+// GetOrCreateKey implements KeyStore.
+func (s *MemoryKeyStore) GetOrCreateKey(_ context.Context, subjectID string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keys := s.keys[subjectID]; len(keys) > 0 {
+		return keys[len(keys)-1], nil
+	}
+	return s.rotate(subjectID)
+}
+
+// GetKey implements KeyStore.
+func (s *MemoryKeyStore) GetKey(_ context.Context, subjectID string, version uint32) (Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findKey(s.keys[subjectID], version)
+}
+
+// DeleteKey implements KeyStore.
+func (s *MemoryKeyStore) DeleteKey(_ context.Context, subjectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, subjectID)
+	return nil
+}
+
+// RotateKey creates a new version of the data key of the given subject and returns it.
+// Previous versions are kept so that existing data can still be decrypted.
+func (s *MemoryKeyStore) RotateKey(_ context.Context, subjectID string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rotate(subjectID)
+}
+
+func (s *MemoryKeyStore) rotate(subjectID string) (Key, error) {
+	key, err := newKey(uint32(len(s.keys[subjectID]) + 1))
+	if err != nil {
+		return Key{}, err
+	}
+	s.keys[subjectID] = append(s.keys[subjectID], key)
+	return key, nil
+}
+
+func newKey(version uint32) (Key, error) {
+	material := make([]byte, KeySize)
+	if _, err := rand.Read(material); err != nil {
+		return Key{}, err
+	}
+	return Key{Version: version, Material: material}, nil
+}
+
+func findKey(keys []Key, version uint32) (Key, error) {
+	if version == 0 || int(version) > len(keys) {
+		return Key{}, ErrKeyNotFound
+	}
+	return keys[version-1], nil
+}
@@ -0,0 +1,96 @@
+package encrypt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// keyRotator is implemented by the key stores of this package.
+type keyRotator interface {
+	KeyStore
+	RotateKey(ctx context.Context, subjectID string) (Key, error)
+}
+
+// testKeyStore runs the common key store test suite.
+func testKeyStore(t *testing.T, ks keyRotator) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if _, err := ks.GetKey(ctx, "abc", 1); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expect err is %v, got %v", ErrKeyNotFound, err)
+	}
+
+	key, err := ks.GetOrCreateKey(ctx, "abc")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if len(key.Material) != KeySize {
+		t.Fatalf("expect key size be %d, got %d", KeySize, len(key.Material))
+	}
+	if key.Version != 1 {
+		t.Fatalf("expect key version be %d, got %d", 1, key.Version)
+	}
+
+	key2, err := ks.GetOrCreateKey(ctx, "abc")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if !bytes.Equal(key.Material, key2.Material) || key.Version != key2.Version {
+		t.Fatal("expect the same key be returned")
+	}
+
+	other, err := ks.GetOrCreateKey(ctx, "def")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if bytes.Equal(key.Material, other.Material) {
+		t.Fatal("expect subjects have different keys")
+	}
+
+	rotated, err := ks.RotateKey(ctx, "abc")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if rotated.Version != 2 || bytes.Equal(key.Material, rotated.Material) {
+		t.Fatalf("expect a new key version be created, got %d", rotated.Version)
+	}
+	latest, err := ks.GetOrCreateKey(ctx, "abc")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if latest.Version != rotated.Version {
+		t.Fatalf("expect latest key version be %d, got %d", rotated.Version, latest.Version)
+	}
+	previous, err := ks.GetKey(ctx, "abc", 1)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if !bytes.Equal(key.Material, previous.Material) {
+		t.Fatal("expect previous key version be kept")
+	}
+	if _, err := ks.GetKey(ctx, "abc", 3); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expect err is %v, got %v", ErrKeyNotFound, err)
+	}
+
+	if err := ks.DeleteKey(ctx, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := ks.DeleteKey(ctx, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	for _, version := range []uint32{1, 2} {
+		if _, err := ks.GetKey(ctx, "abc", version); !errors.Is(err, ErrKeyNotFound) {
+			t.Fatalf("expect err is %v, got %v", ErrKeyNotFound, err)
+		}
+	}
+	if _, err := ks.GetKey(ctx, "def", 1); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+}
+
+func TestMemoryKeyStore(t *testing.T) {
+	testKeyStore(t, NewMemoryKeyStore())
+}
@@ -0,0 +1,198 @@
+package fpe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+var (
+	ErrInvalidRadix  = errors.New("invalid FF1 radix")
+	ErrInvalidLength = errors.New("invalid FF1 input length")
+	ErrInvalidInput  = errors.New("invalid FF1 numeral")
+)
+
+const (
+	minRadix = 2
+	maxRadix = 1 << 16
+
+	// minDomain is the minimum domain size (radix^length) required by NIST SP 800-38G Rev. 1.
+	minDomain = 1_000_000
+)
+
+// FF1 implements the FF1 format-preserving encryption mode as specified by NIST SP 800-38G,
+// using AES as the underlying block cipher.
+//
+// FF1 encrypts numeral strings of a given radix into numeral strings of the same radix and length.
+type FF1 struct {
+	block  cipher.Block
+	radix  int
+	minLen int
+}
+
+// NewFF1 returns a FF1 cipher for the given AES key (16, 24 or 32 bytes) and radix.
+func NewFF1(key []byte, radix int) (*FF1, error) {
+	if radix < minRadix || radix > maxRadix {
+		return nil, fmt.Errorf("%w '%d'", ErrInvalidRadix, radix)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &FF1{
+		block:  block,
+		radix:  radix,
+		minLen: int(math.Ceil(math.Log(minDomain) / math.Log(float64(radix)))),
+	}, nil
+}
+
+// MinLen returns the minimum length of the numeral strings accepted by the cipher.
+func (c *FF1) MinLen() int {
+	return max(c.minLen, 2)
+}
+
+// Encrypt encrypts the given numeral string using the given tweak.
+func (c *FF1) Encrypt(x []uint16, tweak []byte) ([]uint16, error) {
+	return c.cipher(x, tweak, true)
+}
+
+// Decrypt decrypts the given numeral string using the given tweak.
+func (c *FF1) Decrypt(x []uint16, tweak []byte) ([]uint16, error) {
+	return c.cipher(x, tweak, false)
+}
+
+func (c *FF1) cipher(x []uint16, tweak []byte, encrypt bool) ([]uint16, error) {
+	n := len(x)
+	if n < c.MinLen() || n > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: got %d numerals, want at least %d", ErrInvalidLength, n, c.MinLen())
+	}
+	for _, numeral := range x {
+		if int(numeral) >= c.radix {
+			return nil, fmt.Errorf("%w '%d' for radix %d", ErrInvalidInput, numeral, c.radix)
+		}
+	}
+
+	t := len(tweak)
+	u := n / 2
+	v := n - u
+	a, b := x[:u], x[u:]
+
+	byteLen := int(math.Ceil(math.Ceil(float64(v)*math.Log2(float64(c.radix))) / 8))
+	d := 4*((byteLen+3)/4) + 4
+
+	p := make([]byte, 16)
+	p[0], p[1], p[2] = 1, 2, 1
+	p[3], p[4], p[5] = byte(c.radix>>16), byte(c.radix>>8), byte(c.radix)
+	p[6] = 10
+	p[7] = byte(u)
+	binary.BigEndian.PutUint32(p[8:], uint32(n))
+	binary.BigEndian.PutUint32(p[12:], uint32(t))
+
+	// Q = T || [0]^((-t-b-1) mod 16) || [i]^1 || [NUM(B)]^b
+	pad := (16 - (t+byteLen+1)%16) % 16
+	q := make([]byte, t+pad+1+byteLen)
+	copy(q, tweak)
+
+	radix := big.NewInt(int64(c.radix))
+	modU := new(big.Int).Exp(radix, big.NewInt(int64(u)), nil)
+	modV := new(big.Int).Exp(radix, big.NewInt(int64(v)), nil)
+
+	r := make([]byte, 16)
+	s := make([]byte, ((d+15)/16)*16)
+	y, num := new(big.Int), new(big.Int)
+
+	for j := 0; j < 10; j++ {
+		i := j
+		if !encrypt {
+			i = 9 - j
+		}
+
+		// the round input is B when encrypting and A when decrypting.
+		in := b
+		if !encrypt {
+			in = a
+		}
+		q[t+pad] = byte(i)
+		for k := range q[t+pad+1:] {
+			q[t+pad+1+k] = 0
+		}
+		numRadix(in, radix, num).FillBytes(q[t+pad+1:])
+
+		c.prf(r, p, q)
+
+		copy(s, r)
+		for k := 1; k < len(s)/16; k++ {
+			block := s[k*16 : (k+1)*16]
+			copy(block, r)
+			block[15] ^= byte(k)
+			block[14] ^= byte(k >> 8)
+			block[13] ^= byte(k >> 16)
+			block[12] ^= byte(k >> 24)
+			c.block.Encrypt(block, block)
+		}
+		y.SetBytes(s[:d])
+
+		m, mod := u, modU
+		if i%2 == 1 {
+			m, mod = v, modV
+		}
+
+		if encrypt {
+			numRadix(a, radix, num).Add(num, y)
+		} else {
+			numRadix(b, radix, num).Sub(num, y)
+		}
+		num.Mod(num, mod)
+		out := strRadix(num, radix, m)
+
+		if encrypt {
+			a, b = b, out
+		} else {
+			a, b = out, a
+		}
+	}
+
+	return append(append(make([]uint16, 0, n), a...), b...), nil
+}
+
+// prf computes the CBC-MAC of P || Q with a zero IV and writes the result into r.
+func (c *FF1) prf(r, p, q []byte) {
+	for k := range r {
+		r[k] = 0
+	}
+	for _, msg := range [][]byte{p, q} {
+		for k := 0; k < len(msg); k += 16 {
+			for l := 0; l < 16; l++ {
+				r[l] ^= msg[k+l]
+			}
+			c.block.Encrypt(r, r)
+		}
+	}
+}
+
+// numRadix returns the number that the given numeral string represents in the given radix,
+// most significant numeral first.
+func numRadix(x []uint16, radix, z *big.Int) *big.Int {
+	z.SetInt64(0)
+	for _, numeral := range x {
+		z.Mul(z, radix)
+		z.Add(z, big.NewInt(int64(numeral)))
+	}
+	return z
+}
+
+// strRadix returns the representation of x as a numeral string of length m in the given radix.
+func strRadix(x, radix *big.Int, m int) []uint16 {
+	out := make([]uint16, m)
+	x = new(big.Int).Set(x)
+	rem := new(big.Int)
+	for k := m - 1; k >= 0; k-- {
+		x.QuoRem(x, radix, rem)
+		out[k] = uint16(rem.Int64())
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package fpe
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const alphabet36 = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+func toNumerals(s string) []uint16 {
+	x := make([]uint16, len(s))
+	for i, ch := range s {
+		x[i] = uint16(strings.IndexRune(alphabet36, ch))
+	}
+	return x
+}
+
+func fromNumerals(x []uint16) string {
+	var b strings.Builder
+	for _, n := range x {
+		b.WriteByte(alphabet36[n])
+	}
+	return b.String()
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Samples from NIST SP 800-38G (FF1 examples).
+func TestFF1(t *testing.T) {
+	tcs := []struct {
+		key, tweak string
+		radix      int
+		pt, ct     string
+	}{
+		{
+			key:   "2B7E151628AED2A6ABF7158809CF4F3C",
+			radix: 10,
+			pt:    "0123456789",
+			ct:    "2433477484",
+		},
+		{
+			key:   "2B7E151628AED2A6ABF7158809CF4F3C",
+			tweak: "39383736353433323130",
+			radix: 10,
+			pt:    "0123456789",
+			ct:    "6124200773",
+		},
+		{
+			key:   "2B7E151628AED2A6ABF7158809CF4F3C",
+			tweak: "3737373770717273373737",
+			radix: 36,
+			pt:    "0123456789abcdefghi",
+			ct:    "a9tv40mll9kdu509eum",
+		},
+		{
+			key:   "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94",
+			radix: 10,
+			pt:    "0123456789",
+			ct:    "6657667009",
+		},
+		{
+			key:   "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94",
+			tweak: "3737373770717273373737",
+			radix: 36,
+			pt:    "0123456789abcdefghi",
+			ct:    "xs8a0azh2avyalyzuwd",
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			c, err := NewFF1(mustHex(tc.key), tc.radix)
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			ct, err := c.Encrypt(toNumerals(tc.pt), mustHex(tc.tweak))
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if got := fromNumerals(ct); got != tc.ct {
+				t.Fatalf("want %s, got %s", tc.ct, got)
+			}
+			pt, err := c.Decrypt(ct, mustHex(tc.tweak))
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if got := fromNumerals(pt); got != tc.pt {
+				t.Fatalf("want %s, got %s", tc.pt, got)
+			}
+		})
+	}
+}
+
+func TestFF1_Invalid(t *testing.T) {
+	key := mustHex("2B7E151628AED2A6ABF7158809CF4F3C")
+
+	for _, radix := range []int{0, 1, 1<<16 + 1} {
+		if _, err := NewFF1(key, radix); !errors.Is(err, ErrInvalidRadix) {
+			t.Fatalf("expect err is %v, got %v", ErrInvalidRadix, err)
+		}
+	}
+	if _, err := NewFF1([]byte("short"), 10); err == nil {
+		t.Fatal("expect err not to be nil")
+	}
+
+	c, err := NewFF1(key, 10)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want, got := 6, c.MinLen(); want != got {
+		t.Fatalf("want %d, got %d", want, got)
+	}
+	if _, err := c.Encrypt(toNumerals("12345"), nil); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("expect err is %v, got %v", ErrInvalidLength, err)
+	}
+	if _, err := c.Encrypt(toNumerals("12345a"), nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expect err is %v, got %v", ErrInvalidInput, err)
+	}
+}
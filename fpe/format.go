@@ -0,0 +1,204 @@
+/*
+Package fpe implements format-preserving encryption (FPE) of sensitive data based on the FF1 mode (NIST SP 800-38G).
+
+Unlike regular encryption, FPE produces ciphertexts that keep the format of the original data,
+so that they remain valid for downstream systems. Only the variable part of the data,
+as defined by the format of its kind, is encrypted:
+
+  - `email`: the alphanumeric characters of the local part; the domain and the separators are preserved.
+  - `ipv4_addr`: the last three octets; the first octet is preserved.
+  - `credit_card`: the digits except the last four ones; the separators are preserved.
+
+Use [Register] to override or register new formats.
+*/
+package fpe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ln80/struct-sensitive/mask"
+)
+
+var (
+	ErrFormatNotFound = errors.New("format-preserving encryption format not found")
+	ErrInvalidValue   = errors.New("invalid value for format-preserving encryption")
+)
+
+// Format describes the variable part of a specific kind of sensitive data.
+type Format struct {
+	// Radix is the base of the numeral strings returned by Split.
+	Radix int
+
+	// Split extracts the variable part of the given value as a numeral string.
+	// It also returns a join function that rebuilds the value with the given numerals.
+	Split func(val string) (numerals []uint16, join func([]uint16) string, err error)
+}
+
+// Encrypt encrypts the variable part of the given value using the format registered for the given kind.
+// The key must be an AES key of 16, 24 or 32 bytes.
+//
+// It returns [ErrFormatNotFound] if there is no registered format for the kind.
+func Encrypt(key, tweak []byte, kind, val string) (string, error) {
+	return cipherValue(key, tweak, kind, val, true)
+}
+
+// Decrypt decrypts a value encrypted by [Encrypt] using the format registered for the given kind.
+func Decrypt(key, tweak []byte, kind, val string) (string, error) {
+	return cipherValue(key, tweak, kind, val, false)
+}
+
+func cipherValue(key, tweak []byte, kind, val string, encrypt bool) (string, error) {
+	f, ok := Of(kind)
+	if !ok {
+		return "", fmt.Errorf("%w for kind '%s'", ErrFormatNotFound, kind)
+	}
+
+	c, err := NewFF1(key, f.Radix)
+	if err != nil {
+		return "", err
+	}
+
+	numerals, join, err := f.Split(val)
+	if err != nil {
+		return "", err
+	}
+
+	if encrypt {
+		numerals, err = c.Encrypt(numerals, tweak)
+	} else {
+		numerals, err = c.Decrypt(numerals, tweak)
+	}
+	if err != nil {
+		return "", err
+	}
+	return join(numerals), nil
+}
+
+var (
+	formatRegistry map[string]Format = make(map[string]Format)
+	formatMu       sync.RWMutex
+)
+
+// Register registers a format to handle a specific kind of sensitive data.
+func Register(kind string, f Format) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+
+	formatRegistry[kind] = f
+}
+
+// Of returns the format of the given kind.
+func Of(kind string) (f Format, found bool) {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+
+	f, found = formatRegistry[kind]
+	return
+}
+
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// splitAlphabet extracts the characters of the given string that belong to the alphabet as numerals.
+// The other characters are preserved in place by the returned join function.
+func splitAlphabet(s, alphabet string) ([]uint16, func([]uint16) string) {
+	chars := []rune(s)
+	positions := make([]int, 0, len(chars))
+	numerals := make([]uint16, 0, len(chars))
+	for i, ch := range chars {
+		if idx := strings.IndexRune(alphabet, ch); idx >= 0 {
+			positions = append(positions, i)
+			numerals = append(numerals, uint16(idx))
+		}
+	}
+
+	return numerals, func(x []uint16) string {
+		out := append([]rune(nil), chars...)
+		for i, pos := range positions {
+			out[pos] = rune(alphabet[x[i]])
+		}
+		return string(out)
+	}
+}
+
+// Email is the format of `email` kind.
+var Email = Format{
+	Radix: len(alphanumeric),
+	Split: func(val string) ([]uint16, func([]uint16) string, error) {
+		local, domain, err := mask.ParseEmail(val)
+		if err != nil {
+			return nil, nil, err
+		}
+		numerals, join := splitAlphabet(local, alphanumeric)
+		return numerals, func(x []uint16) string {
+			return join(x) + "@" + domain
+		}, nil
+	},
+}
+
+// IPv4Addr is the format of `ipv4_addr` kind.
+var IPv4Addr = Format{
+	Radix: 256,
+	Split: func(val string) ([]uint16, func([]uint16) string, error) {
+		octets, err := mask.ParseIPv4Addr(val)
+		if err != nil {
+			return nil, nil, err
+		}
+		numerals := make([]uint16, 0, 3)
+		for _, octet := range octets[1:] {
+			n, err := strconv.ParseUint(octet, 10, 8)
+			if err != nil {
+				return nil, nil, mask.ErrInvalidIPv4Addr
+			}
+			numerals = append(numerals, uint16(n))
+		}
+		return numerals, func(x []uint16) string {
+			out := []string{octets[0]}
+			for _, n := range x {
+				out = append(out, strconv.Itoa(int(n)))
+			}
+			return strings.Join(out, ".")
+		}, nil
+	},
+}
+
+// CreditCard is the format of `credit_card` kind.
+var CreditCard = Format{
+	Radix: 10,
+	Split: func(val string) ([]uint16, func([]uint16) string, error) {
+		digits := 0
+		for _, ch := range val {
+			switch {
+			case ch >= '0' && ch <= '9':
+				digits++
+			case ch == ' ' || ch == '-':
+			default:
+				return nil, nil, fmt.Errorf("%w: credit card number contains '%c'", ErrInvalidValue, ch)
+			}
+		}
+		if digits < 12 || digits > 19 {
+			return nil, nil, fmt.Errorf("%w: credit card number has %d digits", ErrInvalidValue, digits)
+		}
+
+		// keep the last four digits in cleartext.
+		last := len(val)
+		for visible := 0; visible < 4; last-- {
+			if ch := val[last-1]; ch >= '0' && ch <= '9' {
+				visible++
+			}
+		}
+		numerals, join := splitAlphabet(val[:last], alphanumeric[:10])
+		return numerals, func(x []uint16) string {
+			return join(x) + val[last:]
+		}, nil
+	},
+}
+
+func init() {
+	Register("email", Email)
+	Register("ipv4_addr", IPv4Addr)
+	Register("credit_card", CreditCard)
+}
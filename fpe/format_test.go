@@ -0,0 +1,161 @@
+package fpe_test
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/ln80/struct-sensitive/fpe"
+	"github.com/ln80/struct-sensitive/mask"
+)
+
+func TestEncrypt(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+
+	tcs := []struct {
+		kind   string
+		val    string
+		format *regexp.Regexp
+		ok     bool
+		err    error
+	}{
+		{
+			kind: "unknown",
+			val:  "value",
+			ok:   false,
+			err:  fpe.ErrFormatNotFound,
+		},
+		{
+			kind: "email",
+			val:  "invalid_example.com",
+			ok:   false,
+			err:  mask.ErrInvalidEmail,
+		},
+		{
+			kind: "email",
+			val:  "a.b@example.com",
+			ok:   false,
+			err:  fpe.ErrInvalidLength,
+		},
+		{
+			kind:   "email",
+			val:    "eric.prosacco@example.com",
+			format: regexp.MustCompile(`^[0-9A-Za-z]{4}\.[0-9A-Za-z]{8}@example\.com$`),
+			ok:     true,
+		},
+		{
+			kind: "ipv4_addr",
+			val:  "169.251.10",
+			ok:   false,
+			err:  mask.ErrInvalidIPv4Addr,
+		},
+		{
+			kind:   "ipv4_addr",
+			val:    "169.251.207.194",
+			format: regexp.MustCompile(`^169(\.(25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])){3}$`),
+			ok:     true,
+		},
+		{
+			kind: "credit_card",
+			val:  "4111-1111-abcd-1111",
+			ok:   false,
+			err:  fpe.ErrInvalidValue,
+		},
+		{
+			kind: "credit_card",
+			val:  "4111 1111",
+			ok:   false,
+			err:  fpe.ErrInvalidValue,
+		},
+		{
+			kind:   "credit_card",
+			val:    "4111 1111 1111 1234",
+			format: regexp.MustCompile(`^[0-9]{4} [0-9]{4} [0-9]{4} 1234$`),
+			ok:     true,
+		},
+		{
+			kind:   "credit_card",
+			val:    "4111111111111234",
+			format: regexp.MustCompile(`^[0-9]{12}1234$`),
+			ok:     true,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			ct, err := fpe.Encrypt(key, []byte(tc.kind), tc.kind, tc.val)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if ct == tc.val {
+				t.Fatalf("expect value be encrypted, got %s", ct)
+			}
+			if !tc.format.MatchString(ct) {
+				t.Fatalf("expect %s to match %s", ct, tc.format)
+			}
+
+			// encryption is deterministic for a given key and tweak.
+			ct2, err := fpe.Encrypt(key, []byte(tc.kind), tc.kind, tc.val)
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if ct != ct2 {
+				t.Fatalf("expect %s, %s be equals", ct, ct2)
+			}
+
+			pt, err := fpe.Decrypt(key, []byte(tc.kind), tc.kind, ct)
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if pt != tc.val {
+				t.Fatalf("want %s, got %s", tc.val, pt)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	kind := "test_digits"
+
+	if _, found := fpe.Of(kind); found {
+		t.Fatal("expect not to find format", kind)
+	}
+
+	fpe.Register(kind, fpe.Format{
+		Radix: 10,
+		Split: func(val string) ([]uint16, func([]uint16) string, error) {
+			x := make([]uint16, len(val))
+			for i := range val {
+				x[i] = uint16(val[i] - '0')
+			}
+			return x, func(x []uint16) string {
+				b := make([]byte, len(x))
+				for i, n := range x {
+					b[i] = byte(n) + '0'
+				}
+				return string(b)
+			}, nil
+		},
+	})
+
+	if _, found := fpe.Of(kind); !found {
+		t.Fatal("expect to find format", kind)
+	}
+
+	key := bytes.Repeat([]byte("k"), 16)
+	ct, err := fpe.Encrypt(key, nil, kind, "0123456789")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if !regexp.MustCompile(`^[0-9]{10}$`).MatchString(ct) {
+		t.Fatalf("expect %s be 10 digits", ct)
+	}
+}
@@ -0,0 +1,86 @@
+package sensitive
+
+import (
+	"context"
+	"reflect"
+)
+
+// Redactable is implemented by sensitive data types that know how to redact themselves,
+// e.g. a `PhoneNumber` struct or a `Money` type.
+//
+// [Redact] calls the Redact method of the 'data' fields whose type, or pointer to type, implements it,
+// instead of the redact functions. The method is called on a pointer to the field value.
+//
+// Copies (see [RedactCopy]) deeply clone the exported fields of Redactable structs only,
+// hence the method must not update the values referenced by unexported fields in place.
+type Redactable interface {
+	Redact(fr FieldReplace) error
+}
+
+// Maskable is like [Redactable], but its Mask method is called by [Mask]
+// (i.e. the [WithRegisteredMasks] option) instead of the registered masks.
+//
+// A Maskable type that is not Redactable is redacted to its zero value by [Redact],
+// while a Redactable type that is not Maskable is redacted by [Mask] as well.
+type Maskable interface {
+	Mask(fr FieldReplace) error
+}
+
+// hookReplaceFuncCtx is a callback function executed for each sensitive data field
+// that implements [Redactable] or [Maskable]. The given value is addressable.
+type hookReplaceFuncCtx func(ctx context.Context, fr FieldReplace, v reflect.Value) error
+
+var (
+	redactableType = reflect.TypeFor[Redactable]()
+	maskableType   = reflect.TypeFor[Maskable]()
+)
+
+// isHookType reports whether the given type, or a pointer to it, implements [Redactable] or [Maskable].
+func isHookType(rt reflect.Type) bool {
+	pt := reflect.PointerTo(rt)
+	return pt.Implements(redactableType) || pt.Implements(maskableType)
+}
+
+// hasHook reports whether the sensitive value implements [Redactable] or [Maskable],
+// in which case it is redacted by its own methods.
+func (fr FieldReplace) hasHook() bool {
+	rt := fr.RType
+	if rt == nil || fr.MapKey {
+		return false
+	}
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return isHookType(rt)
+}
+
+// redactHook calls the Mask method of the given value if the mask flag is set and the value is [Maskable],
+// otherwise its Redact method. The value is set to its zero value if it implements none of them.
+func redactHook(fr FieldReplace, v reflect.Value, mask bool) error {
+	p := v.Addr().Interface()
+	if m, ok := p.(Maskable); ok && mask {
+		return m.Mask(fr)
+	}
+	if r, ok := p.(Redactable); ok {
+		return r.Redact(fr)
+	}
+	v.SetZero()
+	return nil
+}
+
+// skipHooks wraps the given redact function so that it leaves the values
+// that implement [Redactable] or [Maskable] untouched.
+func skipHooks(fn ReplaceFuncCtx) ReplaceFuncCtx {
+	return func(ctx context.Context, fr FieldReplace, val string) (string, error) {
+		if fr.hasHook() {
+			return val, nil
+		}
+		return fn(ctx, fr, val)
+	}
+}
+
+func (s sensitiveStruct) replaceHooks(ctx context.Context, fn hookReplaceFuncCtx) error {
+	s.visitor = newVisitor(s.cfg.ErrorOnReferenceCycle)
+	s.hookFn = fn
+	return s.replace(ctx, nil)
+}
@@ -0,0 +1,187 @@
+package sensitive
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type phoneNumber struct {
+	CountryCode string
+	Number      string
+}
+
+func (p *phoneNumber) Redact(fr FieldReplace) error {
+	if p.Number == "invalid" {
+		return errors.New("invalid phone number")
+	}
+	p.Number = strings.Repeat("*", len(p.Number))
+	return nil
+}
+
+func (p *phoneNumber) Mask(fr FieldReplace) error {
+	p.Number = strings.Repeat("*", len(p.Number)-2) + p.Number[len(p.Number)-2:]
+	return nil
+}
+
+type money int64
+
+func (m *money) Redact(fr FieldReplace) error {
+	*m = *m / 100 * 100
+	return nil
+}
+
+type ssn string
+
+func (s ssn) Mask(fr FieldReplace) error {
+	return nil
+}
+
+func TestRedact_Hook(t *testing.T) {
+	type T struct {
+		Phone    phoneNumber            `sensitive:"data"`
+		PhonePtr *phoneNumber           `sensitive:"data"`
+		Phones   []phoneNumber          `sensitive:"data"`
+		Contacts map[string]phoneNumber `sensitive:"data"`
+		Salary   money                  `sensitive:"data"`
+		SSN      ssn                    `sensitive:"data"`
+		Email    string                 `sensitive:"data"`
+	}
+
+	newT := func() *T {
+		return &T{
+			Phone:    phoneNumber{CountryCode: "+32", Number: "470123456"},
+			PhonePtr: &phoneNumber{CountryCode: "+32", Number: "470123456"},
+			Phones:   []phoneNumber{{CountryCode: "+1", Number: "2503080529"}},
+			Contacts: map[string]phoneNumber{"home": {CountryCode: "+1", Number: "2503080529"}},
+			Salary:   52480,
+			SSN:      "123-45-6789",
+			Email:    "email",
+		}
+	}
+
+	type tc struct {
+		val  *T
+		fn   func(any, ...func(*RedactConfig)) error
+		want *T
+	}
+
+	tcs := []tc{
+		{
+			val: newT(),
+			fn:  Redact,
+			want: &T{
+				Phone:    phoneNumber{CountryCode: "+32", Number: "*********"},
+				PhonePtr: &phoneNumber{CountryCode: "+32", Number: "*********"},
+				Phones:   []phoneNumber{{CountryCode: "+1", Number: "**********"}},
+				Contacts: map[string]phoneNumber{"home": {CountryCode: "+1", Number: "**********"}},
+				Salary:   52400,
+				SSN:      "",
+				Email:    "*****",
+			},
+		},
+		{
+			val: newT(),
+			fn:  Mask,
+			want: &T{
+				Phone:    phoneNumber{CountryCode: "+32", Number: "*******56"},
+				PhonePtr: &phoneNumber{CountryCode: "+32", Number: "*******56"},
+				Phones:   []phoneNumber{{CountryCode: "+1", Number: "********29"}},
+				Contacts: map[string]phoneNumber{"home": {CountryCode: "+1", Number: "********29"}},
+				Salary:   52400,
+				SSN:      "123-45-6789",
+				Email:    "*****",
+			},
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			if err := tc.fn(tc.val); err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, tc.val) {
+				t.Fatalf("want %+v, got %+v", tc.want, tc.val)
+			}
+		})
+	}
+}
+
+func TestRedact_Hook_Error(t *testing.T) {
+	type T struct {
+		Phone phoneNumber `sensitive:"data,kind=phone"`
+	}
+
+	val := &T{Phone: phoneNumber{CountryCode: "+32", Number: "invalid"}}
+	if err := Redact(val); err == nil {
+		t.Fatal("expect err be not nil")
+	}
+
+	err := Redact(val, func(rc *RedactConfig) {
+		rc.ContinueOnError = true
+	})
+	var fErr *FieldError
+	if !errors.As(err, &fErr) || fErr.Path != "Phone" || fErr.Kind != "phone" {
+		t.Fatalf("expect err be a FieldError of field 'Phone', got %v", err)
+	}
+	if val.Phone != (phoneNumber{}) {
+		t.Fatalf("expect failing field be zero, got %+v", val.Phone)
+	}
+}
+
+func TestReplace_Hook(t *testing.T) {
+	type T struct {
+		Phone phoneNumber `sensitive:"data"`
+		SSN   ssn         `sensitive:"data"`
+	}
+
+	val := &T{
+		Phone: phoneNumber{CountryCode: "+32", Number: "470123456"},
+		SSN:   "123-45-6789",
+	}
+	s, err := Scan(val, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	// hooks are invoked by Redact and Mask only; string hook types are still replaced as strings.
+	got := []string{}
+	if err := s.Replace(func(fr FieldReplace, val string) (string, error) {
+		got = append(got, fr.Path)
+		return "x", nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want := []string{"SSN"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	if val.Phone.Number != "470123456" || val.SSN != "x" {
+		t.Fatalf("unexpected replaced value %+v", val)
+	}
+}
+
+func TestWalk_Hook(t *testing.T) {
+	type T struct {
+		SSN   ssn         `sensitive:"data"`
+		Phone phoneNumber `sensitive:"data"`
+	}
+
+	s, err := Inspect(T{SSN: "123-45-6789", Phone: phoneNumber{Number: "2503080529"}}, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	// hook values of a string type are walked as any string, unlike the other hook values.
+	got := map[string]string{}
+	if err := s.Walk(func(fr FieldReplace, val string) error {
+		got[fr.Path] = val
+		return nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want := map[string]string{"SSN": "123-45-6789"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,19 @@
+package sensitive
+
+import "encoding/json"
+
+// MarshalJSON returns the JSON encoding of the given struct or struct pointer,
+// in which sensitive data fields are redacted according to the given options.
+//
+// The output is the same as [json.Marshal] (i.e. `json` tag names and options are honored),
+// except for the sensitive fields. The redaction is applied on a copy, hence the original value
+// is left untouched.
+//
+// Use the [WithRegisteredMasks] option to mask sensitive fields instead of redacting them.
+func MarshalJSON(v any, opts ...func(*RedactConfig)) ([]byte, error) {
+	c, err := redactCopy(v, opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(c)
+}
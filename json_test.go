@@ -0,0 +1,103 @@
+package sensitive
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	type Device struct {
+		IPAddr string `json:"ip" sensitive:"data,kind=ipv4_addr"`
+	}
+	type Account struct {
+		ID       string   `json:"id" sensitive:"subjectID"`
+		Email    string   `json:"email" sensitive:"data,kind=email"`
+		Phone    *string  `json:"phone,omitempty" sensitive:"data"`
+		Nickname string   `json:"nickname,omitempty" sensitive:"data"`
+		Devices  []Device `json:"devices" sensitive:"dive"`
+		Role     string   `json:"-"`
+	}
+
+	type tc struct {
+		val    any
+		option func(*RedactConfig)
+		want   string
+		ok     bool
+		err    error
+	}
+
+	tcs := []tc{
+		{
+			val: nil,
+			ok:  false,
+			err: ErrUnsupportedType,
+		},
+		{
+			val: []Account{},
+			ok:  false,
+			err: ErrUnsupportedType,
+		},
+		{
+			val: &InvalidTag{Data: "abc"},
+			ok:  false,
+			err: ErrInvalidTagConfiguration,
+		},
+		{
+			val: Account{
+				ID:    "abc",
+				Email: "email@example.com",
+				Devices: []Device{
+					{IPAddr: "169.251.207.194"},
+				},
+				Role: "Teacher",
+			},
+			want: `{"id":"abc","email":"*****************","devices":[{"ip":"***************"}]}`,
+			ok:   true,
+		},
+		{
+			val: &Account{
+				ID:       "abc",
+				Email:    "email@example.com",
+				Phone:    ptr("250-308-0529"),
+				Nickname: "Kenna",
+				Devices: []Device{
+					{IPAddr: "169.251.207.194"},
+				},
+			},
+			option: WithRegisteredMasks,
+			want:   `{"id":"abc","email":"*****@example.com","phone":"************","nickname":"*****","devices":[{"ip":"169.251.207.***"}]}`,
+			ok:     true,
+		},
+		{
+			val: struct {
+				Avatar  []byte          `json:"avatar" sensitive:"data"`
+				Payload json.RawMessage `json:"payload" sensitive:"data"`
+			}{
+				Avatar:  []byte("avatar"),
+				Payload: json.RawMessage(`{"ssn":"123-45-6789"}`),
+			},
+			want: `{"avatar":null,"payload":null}`,
+			ok:   true,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			got, err := MarshalJSON(tc.val, tc.option)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
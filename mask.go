@@ -1,12 +1,44 @@
 package sensitive
 
-import "github.com/ln80/struct-sensitive/mask"
+import (
+	"fmt"
+
+	"github.com/ln80/struct-sensitive/mask"
+)
+
+// MaskErrorPolicy defines how sensitive values are replaced when a registered mask fails,
+// e.g. when the value does not match the format expected by the mask.
+type MaskErrorPolicy int
+
+const (
+	// OnMaskErrorRedact fully redacts the value using `RedactDefaultFunc`.
+	// It is the default policy, so invalid data never escapes a [Mask] call.
+	OnMaskErrorRedact MaskErrorPolicy = iota
+
+	// OnMaskErrorFail returns the mask error; the redaction stops and the value is left untouched.
+	OnMaskErrorFail
+
+	// OnMaskErrorConstant replaces the value with `RedactConfig.MaskErrorConstant`.
+	OnMaskErrorConstant
+
+	// OnMaskErrorZero replaces the value with the zero value, i.e. an empty string.
+	OnMaskErrorZero
+)
 
 // WithRegisteredMasks returns an option that force redaction using the registered masks,
 // including the predefined one e.g. `email`, `ipv4_addr`, `credit_card`.
 //
 // Use [mask.Register] to override or register new masks.
+//
+// If a mask fails, the value is replaced according to `RedactConfig.OnMaskError` policy;
+// by default, it is fully redacted. A mask also fails if its result is not accepted by the text value
+// it replaces (see [FieldReplace.IsText]).
+//
+// Values that implement [Maskable] are masked by their own Mask method instead.
 func WithRegisteredMasks(rc *RedactConfig) {
+	rc.mask = true
+	rc.RedactFuncCtx = nil
+	rc.BulkRedactFunc = nil
 	rc.RedactFunc = func(fr FieldReplace, val string) (string, error) {
 		if fr.Kind == "" {
 			return RedactDefaultFunc(fr, val)
@@ -15,7 +47,23 @@ func WithRegisteredMasks(rc *RedactConfig) {
 		if !ok {
 			return RedactDefaultFunc(fr, val)
 		}
-		return m(val)
+		masked, err := m(val)
+		if err == nil && fr.IsText() && !validText(fr.RType, masked) {
+			err = fmt.Errorf("%w: masked value of kind '%s'", ErrInvalidTextValue, fr.Kind)
+		}
+		if err == nil {
+			return masked, nil
+		}
+		switch rc.OnMaskError {
+		case OnMaskErrorFail:
+			return "", err
+		case OnMaskErrorConstant:
+			return rc.MaskErrorConstant, nil
+		case OnMaskErrorZero:
+			return "", nil
+		default:
+			return RedactDefaultFunc(fr, val)
+		}
 	}
 }
 
@@ -7,24 +7,36 @@ import (
 	"github.com/ln80/struct-sensitive/internal/option"
 )
 
+var (
+	ErrInvalidEmail = errors.New("invalid email format")
+)
+
 type EmailConfig struct {
 	MaskDomain bool // default false
 }
 
+// ParseEmail splits the given email into its local and domain parts.
+func ParseEmail(email string) (local, domain string, err error) {
+	parts := strings.Split(email, "@")
+	if len(parts) != 2 {
+		return "", "", ErrInvalidEmail
+	}
+	return parts[0], parts[1], nil
+}
+
 func Email(email string, opts ...func(*Config[EmailConfig])) (string, error) {
 	cfg := DefaultConfig(EmailConfig{
 		MaskDomain: false,
 	})
 	option.Apply(&cfg, opts)
 
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
-		return "", errors.New("invalid email format")
+	local, domain, err := ParseEmail(email)
+	if err != nil {
+		return "", err
 	}
 
-	local := strings.Repeat(string([]rune{cfg.Symbol}), len(parts[0]))
+	local = strings.Repeat(string([]rune{cfg.Symbol}), len(local))
 
-	domain := parts[1]
 	if cfg.Kind.MaskDomain {
 		var builder strings.Builder
 		for _, ch := range domain {
@@ -12,6 +12,7 @@ func TestEmail(t *testing.T) {
 		{
 			Value: "invalid_example.com",
 			OK:    false,
+			Err:   mask.ErrInvalidEmail,
 		},
 		{
 			Value: "email.bar@example.com",
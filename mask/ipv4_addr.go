@@ -1,31 +1,42 @@
 package mask
 
 import (
-	"fmt"
+	"errors"
 	"net"
 	"strings"
 
 	"github.com/ln80/struct-sensitive/internal/option"
 )
 
+var (
+	ErrInvalidIPv4Addr = errors.New("invalid IPv4 address")
+)
+
 type IPv4AddrConfig struct {
 	OctetsToMask   int  // default 1
 	OneOctetSymbol bool // default false
 }
 
+// ParseIPv4Addr splits the given IPv4 address into its four octets.
+func ParseIPv4Addr(ip string) ([]string, error) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil || strings.Contains(ip, ":") {
+		return nil, ErrInvalidIPv4Addr
+	}
+	return strings.Split(ip, "."), nil
+}
+
 func IPv4Addr(ip string, opts ...func(*Config[IPv4AddrConfig])) (string, error) {
 	cfg := DefaultConfig(IPv4AddrConfig{
 		OctetsToMask: 1,
 	})
 	option.Apply(&cfg, opts)
 
-	parsedIP := net.ParseIP(ip)
-	if parsedIP == nil || strings.Contains(ip, ":") {
-		return "", fmt.Errorf("invalid IPv4 address")
+	octets, err := ParseIPv4Addr(ip)
+	if err != nil {
+		return "", err
 	}
 
-	octets := strings.Split(ip, ".")
-
 	oneSymbol := string([]rune{cfg.Symbol})
 	threeSymbol := string([]rune{cfg.Symbol, cfg.Symbol, cfg.Symbol})
 	for i := 4 - cfg.Kind.OctetsToMask; i < 4; i++ {
@@ -16,6 +16,7 @@ func TestIPv4Addr(t *testing.T) {
 		{
 			Value: "169.251.10",
 			OK:    false,
+			Err:   mask.ErrInvalidIPv4Addr,
 		},
 		{
 			Value: "169.251.207.194",
@@ -0,0 +1,131 @@
+package mask
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ln80/struct-sensitive/internal/option"
+)
+
+var (
+	ErrInvalidPhone = errors.New("invalid phone number")
+)
+
+const (
+	// minPhoneDigits and maxPhoneDigits bound the number of digits of a phone number,
+	// excluding the international call prefix; E.164 numbers have at most 15 digits.
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+type PhoneConfig struct {
+	VisibleDigits   int  // default 2
+	MaskCountryCode bool // default false
+}
+
+// ParsePhone splits the given phone number into its country code and national number digits.
+//
+// Numbers in international format start with either '+' or the '00' call prefix, e.g. `+32 470 12 34 56`,
+// otherwise they are in national format and the country code is empty, e.g. `(250) 308-0529`.
+// Digits can be separated by spaces, '-', '.', '/' and parentheses.
+func ParsePhone(phone string) (countryCode, number string, err error) {
+	prefix, rest := splitPhonePrefix(phone)
+
+	digits := make([]byte, 0, len(rest))
+	open := false
+	for _, ch := range rest {
+		switch {
+		case ch >= '0' && ch <= '9':
+			digits = append(digits, byte(ch))
+		case ch == '(' && !open:
+			open = true
+		case ch == ')' && open:
+			open = false
+		case ch == ' ', ch == '-', ch == '.', ch == '/':
+		default:
+			return "", "", ErrInvalidPhone
+		}
+	}
+	if open || len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits {
+		return "", "", ErrInvalidPhone
+	}
+
+	if prefix == "" {
+		return "", string(digits), nil
+	}
+	if digits[0] == '0' {
+		return "", "", ErrInvalidPhone
+	}
+	n := countryCodeLen(digits)
+	return string(digits[:n]), string(digits[n:]), nil
+}
+
+// splitPhonePrefix splits the international call prefix, i.e. '+' or '00', from the given phone number.
+func splitPhonePrefix(phone string) (prefix, rest string) {
+	for _, p := range []string{"+", "00"} {
+		if strings.HasPrefix(phone, p) {
+			return p, phone[len(p):]
+		}
+	}
+	return "", phone
+}
+
+// countryCodeLen returns the length of the country code that starts the given digits.
+// Country codes are prefix-free: '1' and '7' are the only 1-digit codes,
+// the 2-digit ones are listed below and the remaining ones have 3 digits.
+func countryCodeLen(digits []byte) int {
+	switch digits[0] {
+	case '1', '7':
+		return 1
+	}
+	switch string(digits[:2]) {
+	case "20", "27",
+		"30", "31", "32", "33", "34", "36", "39",
+		"40", "41", "43", "44", "45", "46", "47", "48", "49",
+		"51", "52", "53", "54", "55", "56", "57", "58",
+		"60", "61", "62", "63", "64", "65", "66",
+		"81", "82", "84", "86",
+		"90", "91", "92", "93", "94", "95", "98":
+		return 2
+	}
+	return 3
+}
+
+// Phone masks the digits of the given phone number, except for the country code and the last digits.
+// The call prefix, the separators and the spacing are preserved, e.g. `+32 470 12 34 56` becomes `+32 *** ** ** 56`.
+func Phone(phone string, opts ...func(*Config[PhoneConfig])) (string, error) {
+	cfg := DefaultConfig(PhoneConfig{
+		VisibleDigits: 2,
+	})
+	option.Apply(&cfg, opts)
+
+	countryCode, number, err := ParsePhone(phone)
+	if err != nil {
+		return "", err
+	}
+
+	visibleFrom := len(countryCode) + len(number) - min(max(cfg.Kind.VisibleDigits, 0), len(number))
+
+	prefix, rest := splitPhonePrefix(phone)
+
+	var builder strings.Builder
+	builder.WriteString(prefix)
+	i := 0
+	for _, ch := range rest {
+		if ch < '0' || ch > '9' {
+			builder.WriteRune(ch)
+			continue
+		}
+		if (i < len(countryCode) && !cfg.Kind.MaskCountryCode) || i >= visibleFrom {
+			builder.WriteRune(ch)
+		} else {
+			builder.WriteRune(cfg.Symbol)
+		}
+		i++
+	}
+	return builder.String(), nil
+}
+
+func init() {
+	Register("phone", DefaultMasker(Phone))
+}
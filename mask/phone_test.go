@@ -0,0 +1,116 @@
+package mask_test
+
+import (
+	"testing"
+
+	"github.com/ln80/struct-sensitive/mask"
+	"github.com/ln80/struct-sensitive/masktest"
+)
+
+func TestPhone(t *testing.T) {
+	masktest.Run(t, mask.Phone, []masktest.Tc[mask.PhoneConfig]{
+		{
+			Value: "call me maybe",
+			OK:    false,
+			Err:   mask.ErrInvalidPhone,
+		},
+		{
+			Value: "+32 470 12 34 5a",
+			OK:    false,
+			Err:   mask.ErrInvalidPhone,
+		},
+		{
+			Value: "12 34 5",
+			OK:    false,
+			Err:   mask.ErrInvalidPhone,
+		},
+		{
+			Value: "+1 (250 308-0529",
+			OK:    false,
+			Err:   mask.ErrInvalidPhone,
+		},
+		{
+			Value: "++32 470 12 34 56",
+			OK:    false,
+			Err:   mask.ErrInvalidPhone,
+		},
+		{
+			Value: "+0 470 12 34 56",
+			OK:    false,
+			Err:   mask.ErrInvalidPhone,
+		},
+		{
+			Value: "+32 470 12 34 56 78 90 12",
+			OK:    false,
+			Err:   mask.ErrInvalidPhone,
+		},
+		{
+			Value: "+32 470 12 34 56",
+			Want:  "+32 *** ** ** 56",
+			OK:    true,
+		},
+		{
+			Value: "+32470123456",
+			Want:  "+32*******56",
+			OK:    true,
+		},
+		{
+			Value: "0032 470 12 34 56",
+			Want:  "0032 *** ** ** 56",
+			OK:    true,
+		},
+		{
+			Value: "+1 (250) 308-0529",
+			Want:  "+1 (***) ***-**29",
+			OK:    true,
+		},
+		{
+			Value: "+213 550 12 34 56",
+			Want:  "+213 *** ** ** 56",
+			OK:    true,
+		},
+		{
+			Value: "0470/12.34.56",
+			Want:  "****/**.**.56",
+			OK:    true,
+		},
+		{
+			Option: func(mc *mask.Config[mask.PhoneConfig]) {
+				mc.Symbol = 'X'
+				mc.Kind.VisibleDigits = 4
+				mc.Kind.MaskCountryCode = true
+			},
+			Value: "+44 20 7946 0958",
+			Want:  "+XX XX XXXX 0958",
+			OK:    true,
+		},
+		{
+			Option: func(mc *mask.Config[mask.PhoneConfig]) {
+				mc.Kind.VisibleDigits = 0
+			},
+			Value: "(250) 308-0529",
+			Want:  "(***) ***-****",
+			OK:    true,
+		},
+	})
+}
+
+func TestParsePhone(t *testing.T) {
+	countryCode, number, err := mask.ParsePhone("+1 (250) 308-0529")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if countryCode != "1" || number != "2503080529" {
+		t.Fatalf("unexpected parsed phone %s, %s", countryCode, number)
+	}
+}
+
+func BenchmarkPhone(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := mask.Phone("+32 470 12 34 56", func(mc *mask.Config[mask.PhoneConfig]) {
+			mc.Kind.VisibleDigits = 4
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
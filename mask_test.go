@@ -5,14 +5,17 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+
+	"github.com/ln80/struct-sensitive/mask"
 )
 
 func TestMask(t *testing.T) {
 	type tc struct {
-		val  any
-		want any
-		ok   bool
-		err  error
+		val    any
+		want   any
+		option func(*RedactConfig)
+		ok     bool
+		err    error
 	}
 	tcs := []tc{
 		{
@@ -35,7 +38,69 @@ func TestMask(t *testing.T) {
 				},
 			},
 			// Mask fails if the predefined mask is incompatible with the sensitive value
-			ok: false,
+			// and the fail policy is used
+			option: func(rc *RedactConfig) {
+				rc.OnMaskError = OnMaskErrorFail
+			},
+			ok:  false,
+			err: mask.ErrInvalidEmail,
+		},
+		{
+			val: &Profile{
+				Email:    "invalid_email.com",
+				Fullname: "Guadalupe Kemmer DDS",
+				Devices: []Device{
+					{
+						IPAddr: "169.251.207.194",
+					},
+					{
+						IPAddr: "c64d:8716:fc03:5fed:4b91:e954:a083:9bad",
+					},
+				},
+			},
+			// by default, values incompatible with the predefined mask are fully redacted
+			want: &Profile{
+				Email:    "*****************",
+				Fullname: "********************",
+				Devices: []Device{
+					{
+						IPAddr: "169.251.207.***",
+					},
+					{
+						IPAddr: "***************************************",
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			val: &Profile{
+				Email:    "invalid_email.com",
+				Fullname: "Guadalupe Kemmer DDS",
+			},
+			option: func(rc *RedactConfig) {
+				rc.OnMaskError = OnMaskErrorConstant
+				rc.MaskErrorConstant = "[INVALID]"
+			},
+			want: &Profile{
+				Email:    "[INVALID]",
+				Fullname: "********************",
+			},
+			ok: true,
+		},
+		{
+			val: &Profile{
+				Email:    "invalid_email.com",
+				Fullname: "Guadalupe Kemmer DDS",
+			},
+			option: func(rc *RedactConfig) {
+				rc.OnMaskError = OnMaskErrorZero
+			},
+			want: &Profile{
+				Email:    "",
+				Fullname: "********************",
+			},
+			ok: true,
 		},
 		{
 			val: &Profile{
@@ -83,11 +148,58 @@ func TestMask(t *testing.T) {
 				ok: true,
 			}
 		}(),
+		func() tc {
+			type T struct {
+				Emails []string          `sensitive:"data,kind=email"`
+				IPs    map[string]string `sensitive:"data,kind=ipv4_addr"`
+			}
+			return tc{
+				val: &T{
+					Emails: []string{"email@example.com", "email.bar@example.com"},
+					IPs:    map[string]string{"home": "169.251.207.194"},
+				},
+				want: &T{
+					Emails: []string{"*****@example.com", "*********@example.com"},
+					IPs:    map[string]string{"home": "169.251.207.***"},
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type Contact struct {
+				Fullname string `sensitive:"data"`
+			}
+			type T struct {
+				Contacts map[string]Contact `sensitive:"dive,keys=email"`
+				Devices  map[Email]string   `sensitive:"data,kind=ipv4_addr,keys=email"`
+			}
+			return tc{
+				val: &T{
+					Contacts: map[string]Contact{
+						"email@example.com":     {Fullname: "Sarah Turcotte"},
+						"email.bar@example.com": {Fullname: "Eric Prosacco"},
+					},
+					Devices: map[Email]string{
+						"email@example.com": "169.251.207.194",
+					},
+				},
+				want: &T{
+					Contacts: map[string]Contact{
+						"*****@example.com":     {Fullname: "**************"},
+						"*********@example.com": {Fullname: "*************"},
+					},
+					Devices: map[Email]string{
+						"*****@example.com": "169.251.207.***",
+					},
+				},
+				ok: true,
+			}
+		}(),
 	}
 
 	for i, tc := range tcs {
 		t.Run("tc: "+strconv.Itoa(i), func(t *testing.T) {
-			err := Mask(tc.val)
+			err := Mask(tc.val, tc.option)
 			if !tc.ok {
 				if err == nil {
 					t.Fatal("expect err not to be nil")
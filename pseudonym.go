@@ -0,0 +1,102 @@
+package sensitive
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ln80/struct-sensitive/internal/option"
+)
+
+var (
+	ErrPseudonymSecretNotFound = errors.New("pseudonymization secret not found")
+)
+
+// PseudonymConfig presents the configuration of the pseudonymization mode.
+type PseudonymConfig struct {
+	// Encoding encodes the HMAC-SHA256 digest into a string.
+	// It defaults to the hexadecimal encoding.
+	Encoding func(digest []byte) string
+
+	// Size truncates the encoded digest to the given number of characters.
+	// It defaults to 0, which means no truncation.
+	Size int
+
+	// KindPrefix prefixes the pseudonym with the kind of the sensitive field, e.g. `email:ab12...`.
+	// It is disabled by default, and has no effect on fields without kind.
+	KindPrefix bool
+}
+
+// WithPseudonymization returns an option that replaces sensitive data with a keyed HMAC-SHA256 digest
+// of their value.
+//
+// Bytes data fields are replaced with the encoded digest as well; raw JSON fields get a JSON string.
+// Text data fields (see [FieldReplace.IsText]) can't hold a digest, e.g. [net/netip.Addr];
+// the redaction returns [ErrUnsupportedFieldType] before replacing anything if the struct has any.
+//
+// The same value always maps to the same pseudonym for a given secret and configuration,
+// which allows to join records across structs and services without revealing the original data.
+// The secret must be kept private; otherwise, pseudonyms are subject to dictionary attacks.
+func WithPseudonymization(secret []byte, opts ...func(*PseudonymConfig)) func(*RedactConfig) {
+	cfg := PseudonymConfig{
+		Encoding: hex.EncodeToString,
+	}
+	option.Apply(&cfg, opts)
+
+	secret = append([]byte(nil), secret...)
+
+	return func(rc *RedactConfig) {
+		rc.pseudonym = true
+		rc.RedactFuncCtx = nil
+		rc.BulkRedactFunc = nil
+		rc.RedactFunc = func(fr FieldReplace, val string) (string, error) {
+			if len(secret) == 0 {
+				return "", ErrPseudonymSecretNotFound
+			}
+			return pseudonymize(secret, cfg, fr.Kind, []byte(val)), nil
+		}
+		rc.RedactBytesFunc = func(fr FieldReplace, val []byte) ([]byte, error) {
+			if len(secret) == 0 {
+				return nil, ErrPseudonymSecretNotFound
+			}
+			pseudonym := pseudonymize(secret, cfg, fr.Kind, val)
+			if fr.IsJSON() {
+				return json.Marshal(pseudonym)
+			}
+			return []byte(pseudonym), nil
+		}
+	}
+}
+
+// checkPseudonymFields returns an error if the struct has text data fields,
+// so that the struct is never left partially pseudonymized.
+func checkPseudonymFields(accessor Struct) error {
+	return accessor.WalkValues(func(fr FieldReplace, val any) error {
+		if fr.IsText() {
+			return fmt.Errorf("%w: text field '%s' of type '%v' can't be pseudonymized", ErrUnsupportedFieldType, fr.Path, fr.RType)
+		}
+		return nil
+	})
+}
+
+func pseudonymize(secret []byte, cfg PseudonymConfig, kind string, val []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(val)
+
+	encode := cfg.Encoding
+	if encode == nil {
+		encode = hex.EncodeToString
+	}
+	pseudonym := encode(mac.Sum(nil))
+	if cfg.Size > 0 && cfg.Size < len(pseudonym) {
+		pseudonym = pseudonym[:cfg.Size]
+	}
+
+	if cfg.KindPrefix && kind != "" {
+		pseudonym = kind + ":" + pseudonym
+	}
+	return pseudonym
+}
@@ -0,0 +1,151 @@
+package sensitive
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestWithPseudonymization(t *testing.T) {
+	type T struct {
+		Email  string   `sensitive:"data,kind=email"`
+		Emails []string `sensitive:"data"`
+	}
+
+	secret := []byte("secret")
+
+	type tc struct {
+		secret []byte
+		opts   []func(*PseudonymConfig)
+		want   *T
+		ok     bool
+		err    error
+	}
+
+	tcs := []tc{
+		{
+			secret: nil,
+			ok:     false,
+			err:    ErrPseudonymSecretNotFound,
+		},
+		{
+			secret: secret,
+			want: &T{
+				Email:  "e3da322f25f9971231d90bf2fc2d8593af9df7964c6fb826d89f891ca53b194a",
+				Emails: []string{"e3da322f25f9971231d90bf2fc2d8593af9df7964c6fb826d89f891ca53b194a"},
+			},
+			ok: true,
+		},
+		{
+			secret: secret,
+			opts: []func(*PseudonymConfig){
+				func(pc *PseudonymConfig) {
+					pc.Size = 8
+					pc.KindPrefix = true
+				},
+			},
+			want: &T{
+				Email:  "email:e3da322f",
+				Emails: []string{"e3da322f"},
+			},
+			ok: true,
+		},
+		{
+			secret: secret,
+			opts: []func(*PseudonymConfig){
+				func(pc *PseudonymConfig) {
+					pc.Encoding = base64.RawURLEncoding.EncodeToString
+					pc.Size = 10
+				},
+			},
+			want: &T{
+				Email:  "49oyLyX5lx",
+				Emails: []string{"49oyLyX5lx"},
+			},
+			ok: true,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			val := &T{
+				Email:  "email@example.com",
+				Emails: []string{"email@example.com"},
+			}
+			err := Redact(val, WithPseudonymization(tc.secret, tc.opts...))
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, val) {
+				t.Fatalf("want %+v, got %+v", tc.want, val)
+			}
+		})
+	}
+}
+
+func TestWithPseudonymization_Secret(t *testing.T) {
+	secret := []byte("secret")
+	opt := WithPseudonymization(secret)
+
+	// mutating the secret after creating the option has no effect.
+	secret[0] = 'S'
+
+	p1, p2 := &Address{Street: "070 a"}, &Address{Street: "070 a"}
+	if err := Redact(p1, opt); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := Redact(p2, WithPseudonymization([]byte("secret"))); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if p1.Street != p2.Street {
+		t.Fatalf("expect the same pseudonym, got %s and %s", p1.Street, p2.Street)
+	}
+
+	p3 := &Address{Street: "070 a"}
+	if err := Redact(p3, WithPseudonymization([]byte("other secret"))); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if p1.Street == p3.Street {
+		t.Fatal("expect pseudonyms differ with different secrets")
+	}
+}
+
+func TestWithPseudonymization_Text(t *testing.T) {
+	type Session struct {
+		Email  string     `sensitive:"data"`
+		IPAddr netip.Addr `sensitive:"data"`
+		Agent  string     `sensitive:"data"`
+	}
+
+	for _, continueOnError := range []bool{false, true} {
+		val := &Session{
+			Email:  "email@example.com",
+			IPAddr: netip.MustParseAddr("169.251.207.194"),
+			Agent:  "curl",
+		}
+		err := Redact(val, WithPseudonymization([]byte("secret")), func(rc *RedactConfig) {
+			rc.ContinueOnError = continueOnError
+		})
+		if !errors.Is(err, ErrUnsupportedFieldType) {
+			t.Fatalf("expect err is %v, got %v", ErrUnsupportedFieldType, err)
+		}
+		// the struct is rejected as a whole rather than partially pseudonymized.
+		want := &Session{
+			Email:  "email@example.com",
+			IPAddr: netip.MustParseAddr("169.251.207.194"),
+			Agent:  "curl",
+		}
+		if !reflect.DeepEqual(want, val) {
+			t.Fatalf("want %+v, got %+v", want, val)
+		}
+	}
+}
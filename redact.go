@@ -1,7 +1,10 @@
 package sensitive
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/ln80/struct-sensitive/internal/option"
@@ -11,6 +14,26 @@ var (
 	ErrRedactFuncNotFound = errors.New("redact function not found")
 )
 
+// FieldError reports a failure to redact a sensitive field, e.g. an error of the redact function
+// or a map key collision (see [ErrMapKeyCollision]).
+type FieldError struct {
+	// Path is the full path of the failing sensitive value (see [FieldReplace.Path]).
+	Path string
+
+	// Kind is the kind of the failing sensitive value.
+	Kind string
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("redact field '%s' (kind '%s'): %v", e.Path, e.Kind, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
 // RedactConfig presents the configuration required by `sensitive.Redact`.
 type RedactConfig struct {
 	// RequireSubjectID force the subjectID resolution from the struct value.
@@ -19,25 +42,95 @@ type RedactConfig struct {
 
 	// RedactFunc overrides the default redaction function `RedactDefaultFunc`.
 	RedactFunc ReplaceFunc
+
+	// RedactFuncCtx is the context-aware variant of RedactFunc.
+	// If set, it takes precedence over RedactFunc.
+	RedactFuncCtx ReplaceFuncCtx
+
+	// RedactScalarFunc overrides the default redaction function of scalar data fields `RedactScalarDefaultFunc`,
+	// i.e. numbers, booleans and [time.Time]. Scalar data fields are left untouched if it is nil.
+	RedactScalarFunc ScalarReplaceFunc
+
+	// RedactBytesFunc overrides the default redaction function of bytes data fields `RedactBytesDefaultFunc`,
+	// e.g. []byte or [encoding/json.RawMessage]. Bytes data fields are left untouched if it is nil.
+	RedactBytesFunc BytesReplaceFunc
+
+	// BulkRedactFunc redacts all the sensitive values of a batch at once; it is only used by [RedactMany].
+	// If not set, [RedactMany] falls back to the per-field redaction function.
+	BulkRedactFunc BulkReplaceFunc
+
+	// ContinueOnError keeps redacting the remaining fields when the redact function fails.
+	// Failing fields are fully redacted using `RedactDefaultFunc` instead, and the returned error
+	// joins a [FieldError] for each one of them, including the map keys dropped due to a collision (see [ErrMapKeyCollision])
+	// and the text values zeroed because they reject their replacement (see [ErrInvalidTextValue]).
+	// This config is disabled by default.
+	ContinueOnError bool
+
+	// OnMaskError defines how values are replaced when a registered mask fails (see [WithRegisteredMasks]).
+	// It defaults to [OnMaskErrorRedact], which fully redacts the value.
+	OnMaskError MaskErrorPolicy
+
+	// MaskErrorConstant is the replacement value used by [OnMaskErrorConstant] policy.
+	MaskErrorConstant string
+
+	// ErrorOnReferenceCycle makes the redaction fail with [ErrReferenceCycle] if a value references itself.
+	// By default, shared references are redacted once and the ones that create a loop are skipped.
+	ErrorOnReferenceCycle bool
+
+	// Strict makes the redaction fail with [ErrUnsupportedFieldType] if a 'sensitive' tag
+	// is set on a field whose type is not supported (see [ScanConfig.Strict]).
+	// This config is disabled by default.
+	Strict bool
+
+	// mask indicates that [Maskable] values are masked rather than redacted; it is set by [WithRegisteredMasks].
+	mask bool
+
+	// pseudonym indicates that values are pseudonymized, which text data fields don't support; it is set by [WithPseudonymization].
+	pseudonym bool
+}
+
+// scanOption applies the relevant redact configuration to the [Scan] configuration.
+func (cfg RedactConfig) scanOption(sc *ScanConfig) {
+	sc.ErrorOnReferenceCycle = cfg.ErrorOnReferenceCycle
+	sc.Strict = cfg.Strict
 }
 
 // Redact redacts sensitive data from struct field values by replacing each character with '*'.
 //
+// Values that implement [Redactable] are redacted by their own Redact method instead.
+//
 // It returns an error if the value is not a struct pointer, the 'sensitive' tag is misconfigured,
 // or if the redact function is nil.
 //
 // Optionally, you can override the default redact function by passing a custom one.
 func Redact(structPtr any, opts ...func(*RedactConfig)) error {
+	return RedactContext(context.Background(), structPtr, opts...)
+}
+
+// RedactContext is like [Redact] but passes the given context to the redact function.
+//
+// The redaction stops and returns the context error as soon as the context is done;
+// in that case, the struct might be partially redacted.
+func RedactContext(ctx context.Context, structPtr any, opts ...func(*RedactConfig)) error {
 	cfg := RedactConfig{
-		RedactFunc: RedactDefaultFunc,
+		RedactFunc:       RedactDefaultFunc,
+		RedactScalarFunc: RedactScalarDefaultFunc,
+		RedactBytesFunc:  RedactBytesDefaultFunc,
 	}
 	option.Apply(&cfg, opts)
 
-	if cfg.RedactFunc == nil {
+	fn := cfg.RedactFuncCtx
+	if fn == nil && cfg.RedactFunc != nil {
+		fn = func(_ context.Context, fr FieldReplace, val string) (string, error) {
+			return cfg.RedactFunc(fr, val)
+		}
+	}
+	if fn == nil {
 		return ErrRedactFuncNotFound
 	}
+	fn = skipHooks(fn)
 
-	accessor, err := Scan(structPtr, cfg.RequireSubjectID)
+	accessor, err := Scan(structPtr, cfg.RequireSubjectID, cfg.scanOption)
 	if err != nil {
 		return err
 	}
@@ -45,10 +138,106 @@ func Redact(structPtr any, opts ...func(*RedactConfig)) error {
 	if !accessor.HasSensitive() {
 		return nil
 	}
+	if cfg.pseudonym {
+		if err := checkPseudonymFields(accessor); err != nil {
+			return err
+		}
+	}
+
+	// map key collisions don't stop the redaction; they are reported once all the fields are redacted.
+	if !cfg.ContinueOnError {
+		fieldErrs, err := accessor.replaceContext(ctx, fn)
+		if err != nil {
+			return err
+		}
+		typedErrs, err := redactTyped(ctx, accessor, cfg, nil)
+		if err != nil {
+			return err
+		}
+		return errors.Join(append(fieldErrs, typedErrs...)...)
+	}
 
-	return accessor.Replace(cfg.RedactFunc)
+	var errs []error
+	fieldErrs, err := accessor.replaceContext(ctx, func(ctx context.Context, fr FieldReplace, val string) (string, error) {
+		newVal, err := fn(ctx, fr, val)
+		if err != nil {
+			errs = append(errs, &FieldError{Path: fr.Path, Kind: fr.Kind, Err: err})
+			return RedactDefaultFunc(fr, val)
+		}
+		return newVal, nil
+	})
+	errs = append(errs, fieldErrs...)
+	if err == nil {
+		fieldErrs, err = redactTyped(ctx, accessor, cfg, &errs)
+		errs = append(errs, fieldErrs...)
+	}
+	return errors.Join(append(errs, err)...)
+}
+
+// redactTyped redacts the scalar and bytes data fields using the configured redact functions,
+// then the data fields that implement [Redactable] or [Maskable] using their own methods.
+// If the errs parameter is not nil, failing fields are set to their zero value and their errors are appended to it.
+//
+// Scalar values that can't be redacted within the range of their type (see [ErrInvalidScalarValue]) are set
+// to their zero value in any case, and returned as field errors that did not stop the redaction.
+func redactTyped(ctx context.Context, accessor Struct, cfg RedactConfig, errs *[]error) (fieldErrs []error, err error) {
+	if cfg.RedactScalarFunc != nil {
+		rejected, err := accessor.replaceScalarContext(ctx, func(_ context.Context, fr FieldReplace, val any) (any, error) {
+			if fr.hasHook() {
+				return val, nil
+			}
+			newVal, err := cfg.RedactScalarFunc(fr, val)
+			if err != nil && (errs != nil || errors.Is(err, ErrInvalidScalarValue)) {
+				fieldErr := &FieldError{Path: fr.Path, Kind: fr.Kind, Err: err}
+				if errs != nil {
+					*errs = append(*errs, fieldErr)
+				} else {
+					fieldErrs = append(fieldErrs, fieldErr)
+				}
+				return nil, nil
+			}
+			return newVal, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		fieldErrs = append(fieldErrs, rejected...)
+	}
+	if cfg.RedactBytesFunc != nil {
+		if err := accessor.ReplaceBytesContext(ctx, func(_ context.Context, fr FieldReplace, val []byte) ([]byte, error) {
+			if fr.hasHook() {
+				return val, nil
+			}
+			newVal, err := cfg.RedactBytesFunc(fr, val)
+			if err != nil && errs != nil {
+				*errs = append(*errs, &FieldError{Path: fr.Path, Kind: fr.Kind, Err: err})
+				return nil, nil
+			}
+			return newVal, err
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := accessor.replaceHooks(ctx, func(_ context.Context, fr FieldReplace, v reflect.Value) error {
+		err := redactHook(fr, v, cfg.mask)
+		if err != nil && errs != nil {
+			*errs = append(*errs, &FieldError{Path: fr.Path, Kind: fr.Kind, Err: err})
+			v.SetZero()
+			return nil
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return fieldErrs, nil
 }
 
-func RedactDefaultFunc(_ FieldReplace, val string) (string, error) {
+// RedactDefaultFunc replaces each character of the value with '*'.
+// Text values (see [FieldReplace.IsText]) are redacted to their zero value instead,
+// since they may not accept such a replacement.
+func RedactDefaultFunc(fr FieldReplace, val string) (string, error) {
+	if fr.IsText() {
+		return "", nil
+	}
 	return strings.Repeat("*", len(val)), nil
 }
@@ -1,7 +1,9 @@
 package sensitive
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
 	"testing"
@@ -150,6 +152,158 @@ func TestRedact(t *testing.T) {
 				ok: true,
 			}
 		}(),
+		func() tc {
+			type T struct {
+				Emails    []Email           `sensitive:"data,kind=email"`
+				Codes     [2]string         `sensitive:"data"`
+				Phones    []*string         `sensitive:"data"`
+				Aliases   map[string]string `sensitive:"data"`
+				Addresses map[int]*string   `sensitive:"data"`
+				Notes     *[]string         `sensitive:"data"`
+				Tags      []string
+			}
+			return tc{
+				val: &T{
+					Emails:    []Email{"email@example.com", ""},
+					Codes:     [2]string{"abc", "de"},
+					Phones:    []*string{ptr("250-308-0529"), nil},
+					Aliases:   map[string]string{"a": "Sarah", "b": ""},
+					Addresses: map[int]*string{1: ptr("07024 Quigley Trace"), 2: nil},
+					Notes:     &[]string{"note"},
+					Tags:      []string{"tag"},
+				},
+				want: &T{
+					Emails:    []Email{"*****************", ""},
+					Codes:     [2]string{"***", "**"},
+					Phones:    []*string{ptr("************"), nil},
+					Aliases:   map[string]string{"a": "*****", "b": ""},
+					Addresses: map[int]*string{1: ptr("*******************"), 2: nil},
+					Notes:     &[]string{"****"},
+					Tags:      []string{"tag"},
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Emails  []Email           `sensitive:"data"`
+				Aliases map[string]string `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Emails:  []Email{"email@example.com", "email2@example.com"},
+					Aliases: map[string]string{"a": "Sarah"},
+				},
+				want: &T{
+					Emails:  []Email{"Email:0", "Email:1"},
+					Aliases: map[string]string{"a": "string:a"},
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactFunc = func(fr FieldReplace, val string) (string, error) {
+						return fmt.Sprintf("%s:%v", fr.RType.Name(), fr.Key), nil
+					}
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Aliases map[string]int `sensitive:"data,keys=name"`
+			}
+			return tc{
+				val: &T{
+					Aliases: map[string]int{"Sarah": 1, "Eric": 2},
+				},
+				// scalar values are redacted as well using the default scalar redaction.
+				want: &T{
+					Aliases: map[string]int{"name:Sarah": 0, "name:Eric": 0},
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactFunc = func(fr FieldReplace, val string) (string, error) {
+						if !fr.MapKey || fr.Key != val {
+							return "", errors.New("unexpected field replace")
+						}
+						return fr.Kind + ":" + val, nil
+					}
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Aliases map[string]string `sensitive:"data,keys="`
+			}
+			return tc{
+				val: &T{
+					Aliases: map[string]string{"Sarah": "a", "Turco": "b"},
+				},
+				ok:  false,
+				err: ErrMapKeyCollision,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Aliases []string `sensitive:"data,keys=name"`
+			}
+			return tc{
+				val: &T{
+					Aliases: []string{"Sarah"},
+				},
+				ok:  false,
+				err: ErrInvalidTagConfiguration,
+			}
+		}(),
+		func() tc {
+			type Event interface{}
+			type T struct {
+				Payload  any            `sensitive:"dive"`
+				Event    Event          `sensitive:"dive"`
+				Events   []any          `sensitive:"dive"`
+				Contacts map[string]any `sensitive:"dive"`
+				Meta     any            `sensitive:"dive"`
+			}
+			return tc{
+				val: &T{
+					Payload: &Address{Street: "07024 Quigley Trace"},
+					Event:   Address{Street: "7234 Antone Springs"},
+					Events: []any{
+						Address{Street: "90 Kerluke Pine"},
+						nil,
+						"Teacher",
+					},
+					Contacts: map[string]any{
+						"A": Address{Street: "90 Kerluke Pine"},
+					},
+					Meta: struct{ Role string }{Role: "Teacher"},
+				},
+				want: &T{
+					Payload: &Address{Street: "*******************"},
+					Event:   Address{Street: "*******************"},
+					Events: []any{
+						Address{Street: "***************"},
+						nil,
+						"Teacher",
+					},
+					Contacts: map[string]any{
+						"A": Address{Street: "***************"},
+					},
+					Meta: struct{ Role string }{Role: "Teacher"},
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Payload any `sensitive:"dive"`
+			}
+			return tc{
+				val: &T{
+					Payload: &InvalidTag{Data: "abc"},
+				},
+				ok:  false,
+				err: ErrInvalidTagConfiguration,
+			}
+		}(),
 	}
 
 	for i, tc := range tcs {
@@ -176,3 +330,230 @@ func TestRedact(t *testing.T) {
 		})
 	}
 }
+
+func TestRedactContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("context is passed to the redact func", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "req-1")
+		val := &Profile{
+			Email: "email@example.com",
+			Devices: []Device{
+				{IPAddr: "169.251.207.194"},
+			},
+		}
+		calls := 0
+		err := RedactContext(ctx, val, func(rc *RedactConfig) {
+			rc.RedactFuncCtx = func(ctx context.Context, fr FieldReplace, val string) (string, error) {
+				calls++
+				return ctx.Value(ctxKey{}).(string), nil
+			}
+		})
+		if err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+		if calls != 2 {
+			t.Fatalf("expect redact func be called 2 times, got %d", calls)
+		}
+		if val.Email != "req-1" || val.Devices[0].IPAddr != "req-1" {
+			t.Fatalf("expect values be replaced, got %+v", val)
+		}
+	})
+
+	t.Run("cancelled context aborts redaction", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		val := &Profile{
+			Email:    "email@example.com",
+			Fullname: "Guadalupe Kemmer DDS",
+		}
+		err := RedactContext(ctx, val, func(rc *RedactConfig) {
+			rc.RedactFuncCtx = func(ctx context.Context, fr FieldReplace, val string) (string, error) {
+				cancel()
+				return "", nil
+			}
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expect err is %v, got %v", context.Canceled, err)
+		}
+		if val.Fullname != "Guadalupe Kemmer DDS" {
+			t.Fatalf("expect traversal be aborted, got %+v", val)
+		}
+	})
+
+	t.Run("registered masks take precedence over a previous redact func", func(t *testing.T) {
+		val := &Profile{Email: "email@example.com"}
+		err := RedactContext(context.Background(), val, func(rc *RedactConfig) {
+			rc.RedactFuncCtx = func(ctx context.Context, fr FieldReplace, val string) (string, error) {
+				return "", nil
+			}
+		}, WithRegisteredMasks)
+		if err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+		if want := Email("*****@example.com"); val.Email != want {
+			t.Fatalf("want %s, got %s", want, val.Email)
+		}
+	})
+}
+
+func TestRedact_ContinueOnError(t *testing.T) {
+	val := &Profile{
+		Email:    "invalid_email.com",
+		Fullname: "Guadalupe Kemmer DDS",
+		Devices: []Device{
+			{
+				IPAddr: "169.251.207.194",
+			},
+			{
+				IPAddr: "c64d:8716:fc03:5fed:4b91:e954:a083:9bad",
+			},
+		},
+	}
+
+	failPolicy := func(rc *RedactConfig) {
+		rc.OnMaskError = OnMaskErrorFail
+	}
+
+	// without the option, the redaction stops at the first error
+	if err := Mask(ptr(*val), failPolicy); err == nil {
+		t.Fatal("expect err not to be nil")
+	}
+
+	err := Mask(val, failPolicy, func(rc *RedactConfig) {
+		rc.ContinueOnError = true
+	})
+	if err == nil {
+		t.Fatal("expect err not to be nil")
+	}
+
+	want := &Profile{
+		Email:    "*****************",
+		Fullname: "********************",
+		Devices: []Device{
+			{
+				IPAddr: "169.251.207.***",
+			},
+			{
+				IPAddr: "***************************************",
+			},
+		},
+	}
+	if !reflect.DeepEqual(want, val) {
+		t.Fatalf("want %+v, got %+v", want, val)
+	}
+
+	var fieldErrs []*FieldError
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("expect err be a field error, got %v", err)
+		}
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
+	if l := len(fieldErrs); l != 2 {
+		t.Fatalf("expect %d field errors, got %d", 2, l)
+	}
+	if fe := fieldErrs[0]; fe.Path != "Email" || fe.Kind != "email" {
+		t.Fatalf("expect field error on %s (%s), got %s (%s)", "Email", "email", fe.Path, fe.Kind)
+	}
+	if fe := fieldErrs[1]; fe.Path != "Devices[1].IPAddr" || fe.Kind != "ipv4_addr" {
+		t.Fatalf("expect field error on %s (%s), got %s (%s)", "Devices[1].IPAddr", "ipv4_addr", fe.Path, fe.Kind)
+	}
+}
+
+func TestRedact_MapKeyCollision(t *testing.T) {
+	type T struct {
+		Contacts map[string]string `sensitive:"data,keys=email"`
+		Secret   string            `sensitive:"data"`
+		Age      int               `sensitive:"data"`
+	}
+
+	newT := func() *T {
+		return &T{
+			Contacts: map[string]string{"ab@x.com": "a", "cd@x.com": "b", "eric@x.com": "c"},
+			Secret:   "secret",
+			Age:      36,
+		}
+	}
+
+	type tc struct {
+		fn   func(any, ...func(*RedactConfig)) error
+		want *T
+	}
+
+	tcs := []tc{
+		{
+			fn: Redact,
+			want: &T{
+				Contacts: map[string]string{"**********": "*"},
+				Secret:   "******",
+			},
+		},
+		{
+			fn: Mask,
+			want: &T{
+				Contacts: map[string]string{"****@x.com": "*"},
+				Secret:   "******",
+			},
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			val := newT()
+			err := tc.fn(val)
+			var fieldErr *FieldError
+			if !errors.Is(err, ErrMapKeyCollision) || !errors.As(err, &fieldErr) {
+				t.Fatalf("expect err be a field error of %v, got %v", ErrMapKeyCollision, err)
+			}
+			if fieldErr.Path != "Contacts#key" || fieldErr.Kind != "email" {
+				t.Fatalf("unexpected field error %+v", fieldErr)
+			}
+			// colliding entries are dropped, and the remaining fields are redacted.
+			if !reflect.DeepEqual(tc.want, val) {
+				t.Fatalf("want %+v, got %+v", tc.want, val)
+			}
+		})
+	}
+}
+
+func TestRedact_ContinueOnError_MapKeyCollision(t *testing.T) {
+	type T struct {
+		Contacts map[string]string `sensitive:"data,keys=email"`
+		Email    string            `sensitive:"data,kind=email"`
+		Age      int               `sensitive:"data"`
+	}
+
+	val := &T{
+		Contacts: map[string]string{"ab@x.com": "a", "cd@x.com": "b"},
+		Email:    "invalid_email.com",
+		Age:      36,
+	}
+	err := Mask(val, func(rc *RedactConfig) {
+		rc.OnMaskError = OnMaskErrorFail
+		rc.ContinueOnError = true
+	})
+
+	want := &T{
+		Contacts: map[string]string{},
+		Email:    "*****************",
+	}
+	if !reflect.DeepEqual(want, val) {
+		t.Fatalf("want %+v, got %+v", want, val)
+	}
+
+	var paths []string
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("expect err be a field error, got %v", err)
+		}
+		paths = append(paths, fieldErr.Path)
+	}
+	if want := []string{"Email", "Contacts#key"}; !reflect.DeepEqual(want, paths) {
+		t.Fatalf("want field errors on %v, got %v", want, paths)
+	}
+	if !errors.Is(err, ErrMapKeyCollision) {
+		t.Fatalf("expect err is %v, got %v", ErrMapKeyCollision, err)
+	}
+}
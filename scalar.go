@@ -0,0 +1,254 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidScalarValue = errors.New("invalid sensitive scalar replacement value")
+)
+
+// ScalarReplaceFunc is a callback function executed by the [Struct.ReplaceScalar] method
+// for each sensitive data field of a scalar type, i.e. numbers, booleans and [time.Time].
+//
+// It receives the original value normalized to one of these types: int64, uint64, float64, bool, time.Time,
+// and returns the new value, which must be convertible to the original type of the field (see [FieldReplace.RType]).
+type ScalarReplaceFunc func(fr FieldReplace, val any) (any, error)
+
+// ScalarReplaceFuncCtx is the context-aware variant of [ScalarReplaceFunc] executed by the [Struct.ReplaceScalarContext] method.
+type ScalarReplaceFuncCtx func(ctx context.Context, fr FieldReplace, val any) (any, error)
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+)
+
+// isScalarType reports whether the given type is a supported scalar data type.
+func isScalarType(rt reflect.Type) bool {
+	if rt == timeType {
+		return true
+	}
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.Bool:
+		return true
+	}
+	return false
+}
+
+// scalarValue returns the normalized value of the given scalar value.
+func scalarValue(v reflect.Value) any {
+	if v.Type() == timeType {
+		return v.Interface()
+	}
+	switch {
+	case v.CanInt():
+		return v.Int()
+	case v.CanUint():
+		return v.Uint()
+	case v.CanFloat():
+		return v.Float()
+	default:
+		return v.Bool()
+	}
+}
+
+// setScalarValue converts the given value to the type of the scalar value and sets it.
+func setScalarValue(v reflect.Value, val any) error {
+	rv := reflect.ValueOf(val)
+	if !rv.IsValid() {
+		v.SetZero()
+		return nil
+	}
+	if !rv.CanConvert(v.Type()) {
+		return fmt.Errorf("%w: '%v' is not convertible to '%v'", ErrInvalidScalarValue, rv.Type(), v.Type())
+	}
+	if scalarOverflows(v.Type(), rv) {
+		return fmt.Errorf("%w: '%v' overflows '%v'", ErrInvalidScalarValue, val, v.Type())
+	}
+	v.Set(rv.Convert(v.Type()))
+	return nil
+}
+
+// scalarOverflows reports whether the given number can't be represented by the given numeric type.
+func scalarOverflows(rt reflect.Type, rv reflect.Value) bool {
+	v := reflect.New(rt).Elem()
+	switch {
+	case v.CanInt():
+		switch {
+		case rv.CanInt():
+			return v.OverflowInt(rv.Int())
+		case rv.CanUint():
+			return rv.Uint() > math.MaxInt64 || v.OverflowInt(int64(rv.Uint()))
+		case rv.CanFloat():
+			f := math.Trunc(rv.Float())
+			return math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 || v.OverflowInt(int64(f))
+		}
+	case v.CanUint():
+		switch {
+		case rv.CanInt():
+			return rv.Int() < 0 || v.OverflowUint(uint64(rv.Int()))
+		case rv.CanUint():
+			return v.OverflowUint(rv.Uint())
+		case rv.CanFloat():
+			f := math.Trunc(rv.Float())
+			return math.IsNaN(f) || f < 0 || f >= math.MaxUint64 || v.OverflowUint(uint64(f))
+		}
+	case v.CanFloat():
+		if rv.CanFloat() {
+			return v.OverflowFloat(rv.Float())
+		}
+	}
+	return false
+}
+
+// RedactScalarDefaultFunc redacts scalar data fields according to the strategy defined in the 'sensitive' tag options:
+//
+//   - `bucket=N` rounds down numbers to a multiple of N, e.g. `sensitive:"data,bucket=1000"`.
+//   - `round=N` rounds numbers to the nearest multiple of N, e.g. `sensitive:"data,round=0.5"`.
+//   - `truncate=year|month|day` truncates dates, e.g. `sensitive:"data,truncate=year"`.
+//
+// By default, the value is replaced with the zero value of its type. The options are validated when the struct type
+// is scanned, e.g. by [Check]: `bucket` and `round` require a number, and `truncate` requires a [time.Time].
+func RedactScalarDefaultFunc(fr FieldReplace, val any) (any, error) {
+	switch v := val.(type) {
+	case int64:
+		return intStrategy(fr, v)
+	case uint64:
+		return uintStrategy(fr, v)
+	case float64:
+		return scalarStrategy(fr, v, func(f float64) any { return f })
+	case time.Time:
+		switch opt := fr.Options.Get("truncate"); opt {
+		case "":
+			return time.Time{}, nil
+		case "year":
+			return time.Date(v.Year(), 1, 1, 0, 0, 0, 0, v.Location()), nil
+		case "month":
+			return time.Date(v.Year(), v.Month(), 1, 0, 0, 0, 0, v.Location()), nil
+		case "day":
+			return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, v.Location()), nil
+		default:
+			return nil, fmt.Errorf("%w: invalid 'truncate' option '%s' in '%s'", ErrInvalidTagConfiguration, opt, fr.Path)
+		}
+	default:
+		return reflect.Zero(reflect.TypeOf(val)).Interface(), nil
+	}
+}
+
+// validateScalarOptions checks the redaction strategy options of the given data field, whose value type is rt,
+// so that a misconfigured strategy is reported at scan time rather than when a value is redacted.
+func validateScalarOptions(field reflect.StructField, rt reflect.Type, opts TagOptions) error {
+	numeric := isScalarType(rt) && rt != timeType && rt.Kind() != reflect.Bool
+	for _, opt := range []string{"bucket", "round"} {
+		str, ok := opts[opt]
+		if !ok {
+			continue
+		}
+		if !numeric {
+			return fmt.Errorf("'%s' option requires a numeric field, found '%v' in '%s'", opt, field.Type, field.Name)
+		}
+		if n, err := strconv.ParseFloat(str, 64); err != nil || !(n > 0) || math.IsInf(n, 0) {
+			return fmt.Errorf("invalid '%s' option '%s' in '%s'", opt, str, field.Name)
+		}
+	}
+	if str, ok := opts["truncate"]; ok {
+		if rt != timeType {
+			return fmt.Errorf("'truncate' option requires a time field, found '%v' in '%s'", field.Type, field.Name)
+		}
+		switch str {
+		case "year", "month", "day":
+		default:
+			return fmt.Errorf("invalid 'truncate' option '%s' in '%s'", str, field.Name)
+		}
+	}
+	return nil
+}
+
+// numericStrategy returns the numeric strategy defined in the tag options, i.e. `bucket` or `round`, and its option value.
+// It returns an empty strategy if none is defined.
+func numericStrategy(fr FieldReplace) (opt, str string) {
+	for _, opt := range []string{"bucket", "round"} {
+		if str := fr.Options.Get(opt); str != "" {
+			return opt, str
+		}
+	}
+	return "", ""
+}
+
+// scalarStrategy applies the numeric strategy defined in the tag options to the given number.
+func scalarStrategy(fr FieldReplace, val float64, conv func(float64) any) (any, error) {
+	opt, str := numericStrategy(fr)
+	if opt == "" {
+		return conv(0), nil
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("%w: invalid '%s' option '%s' in '%s'", ErrInvalidTagConfiguration, opt, str, fr.Path)
+	}
+	if opt == "bucket" {
+		return conv(math.Floor(val/n) * n), nil
+	}
+	return conv(math.Round(val/n) * n), nil
+}
+
+// intStrategy is like scalarStrategy, but it relies on integer arithmetic to not lose precision
+// on large numbers, unless the option value is not an integer.
+func intStrategy(fr FieldReplace, val int64) (any, error) {
+	opt, str := numericStrategy(fr)
+	if opt == "" {
+		return int64(0), nil
+	}
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || n <= 0 {
+		return scalarStrategy(fr, float64(val), func(f float64) any { return f })
+	}
+
+	overflow := fmt.Errorf("%w: '%s' option '%s' overflows '%d' in '%s'", ErrInvalidScalarValue, opt, str, val, fr.Path)
+	// r is the distance to the multiple of n just below the value.
+	r := val % n
+	if r < 0 {
+		r += n
+	}
+	if opt == "round" && r >= n-r {
+		// round half away from zero, like math.Round.
+		if r > n-r || val >= 0 {
+			if val > math.MaxInt64-(n-r) {
+				return nil, overflow
+			}
+			return val + (n - r), nil
+		}
+	}
+	if val < math.MinInt64+r {
+		return nil, overflow
+	}
+	return val - r, nil
+}
+
+// uintStrategy is the unsigned variant of intStrategy.
+func uintStrategy(fr FieldReplace, val uint64) (any, error) {
+	opt, str := numericStrategy(fr)
+	if opt == "" {
+		return uint64(0), nil
+	}
+	n, err := strconv.ParseUint(str, 10, 64)
+	if err != nil || n == 0 {
+		return scalarStrategy(fr, float64(val), func(f float64) any { return f })
+	}
+
+	r := val % n
+	if opt == "round" && r >= n-r {
+		if val > math.MaxUint64-(n-r) {
+			return nil, fmt.Errorf("%w: '%s' option '%s' overflows '%d' in '%s'", ErrInvalidScalarValue, opt, str, val, fr.Path)
+		}
+		return val + (n - r), nil
+	}
+	return val - r, nil
+}
@@ -0,0 +1,364 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRedact_Scalar(t *testing.T) {
+	type Salary int64
+
+	type tc struct {
+		val    any
+		want   any
+		option func(*RedactConfig)
+		ok     bool
+		err    error
+	}
+
+	birthDate := time.Date(1987, time.June, 14, 10, 30, 0, 0, time.UTC)
+
+	tcs := []tc{
+		func() tc {
+			type T struct {
+				Age       int               `sensitive:"data"`
+				Salary    Salary            `sensitive:"data,bucket=1000"`
+				Weight    *float64          `sensitive:"data,round=5"`
+				Score     float32           `sensitive:"data,bucket=0.5"`
+				Visits    uint              `sensitive:"data,round=10"`
+				HasHIV    bool              `sensitive:"data"`
+				BirthDate time.Time         `sensitive:"data,truncate=year"`
+				JoinedAt  *time.Time        `sensitive:"data,truncate=month"`
+				LastSeen  time.Time         `sensitive:"data"`
+				Temps     []float64         `sensitive:"data,round=1"`
+				Balances  map[string]int    `sensitive:"data,bucket=100"`
+				Tags      map[string]string `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Age:       36,
+					Salary:    52480,
+					Weight:    ptr(72.6),
+					Score:     3.7,
+					Visits:    14,
+					HasHIV:    true,
+					BirthDate: birthDate,
+					JoinedAt:  ptr(birthDate),
+					LastSeen:  birthDate,
+					Temps:     []float64{36.6, 38.4},
+					Balances:  map[string]int{"main": 1250, "savings": -130},
+					Tags:      map[string]string{"a": "vip"},
+				},
+				want: &T{
+					Age:       0,
+					Salary:    52000,
+					Weight:    ptr(75.0),
+					Score:     3.5,
+					Visits:    10,
+					HasHIV:    false,
+					BirthDate: time.Date(1987, time.January, 1, 0, 0, 0, 0, time.UTC),
+					JoinedAt:  ptr(time.Date(1987, time.June, 1, 0, 0, 0, 0, time.UTC)),
+					LastSeen:  time.Time{},
+					Temps:     []float64{37, 38},
+					Balances:  map[string]int{"main": 1200, "savings": -200},
+					Tags:      map[string]string{"a": "***"},
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Age    int  `sensitive:"data"`
+				HasHIV bool `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Age:    36,
+					HasHIV: true,
+				},
+				want: &T{
+					Age:    36,
+					HasHIV: true,
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactScalarFunc = nil
+				},
+				ok: true,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Age int `sensitive:"data,bucket=abc"`
+			}
+			return tc{
+				val: &T{
+					Age: 36,
+				},
+				ok:  false,
+				err: ErrInvalidTagConfiguration,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				BirthDate time.Time `sensitive:"data,truncate=week"`
+			}
+			return tc{
+				val: &T{
+					BirthDate: birthDate,
+				},
+				ok:  false,
+				err: ErrInvalidTagConfiguration,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Age int `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Age: 36,
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactScalarFunc = func(fr FieldReplace, val any) (any, error) {
+						return "thirty-six", nil
+					}
+				},
+				ok:  false,
+				err: ErrInvalidScalarValue,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Score int8 `sensitive:"data,round=10"`
+			}
+			return tc{
+				val: &T{
+					Score: 127,
+				},
+				ok:  false,
+				err: ErrInvalidScalarValue,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				Visits uint8 `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Visits: 3,
+				},
+				option: func(rc *RedactConfig) {
+					rc.RedactScalarFunc = func(fr FieldReplace, val any) (any, error) {
+						return int64(-1), nil
+					}
+				},
+				ok:  false,
+				err: ErrInvalidScalarValue,
+			}
+		}(),
+		func() tc {
+			type T struct {
+				ID     int64  `sensitive:"data,bucket=10"`
+				Serial uint64 `sensitive:"data,round=10"`
+				Debt   int64  `sensitive:"data,round=10"`
+			}
+			return tc{
+				val: &T{
+					ID:     9007199254740993,
+					Serial: 18446744073709551605,
+					Debt:   -9007199254740995,
+				},
+				// integers are bucketed without losing precision above 2^53.
+				want: &T{
+					ID:     9007199254740990,
+					Serial: 18446744073709551610,
+					Debt:   -9007199254741000,
+				},
+				ok: true,
+			}
+		}(),
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			err := Redact(tc.val, tc.option)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, tc.val) {
+				t.Fatalf("want %+v, got %+v", tc.want, tc.val)
+			}
+		})
+	}
+}
+
+func TestReplaceScalar(t *testing.T) {
+	type Salary int64
+	type T struct {
+		Profile `sensitive:"dive"`
+		Salary  Salary    `sensitive:"data,kind=salary"`
+		Ratios  []float64 `sensitive:"data"`
+	}
+
+	val := &T{
+		Profile: Profile{Email: "email@example.com"},
+		Salary:  52480,
+		Ratios:  []float64{0.5},
+	}
+
+	s, err := Scan(val, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	got := map[string]any{}
+	if err := s.ReplaceScalar(func(fr FieldReplace, val any) (any, error) {
+		got[fr.Path] = val
+		if fr.Kind == "salary" && fr.RType != reflect.TypeFor[Salary]() {
+			t.Fatalf("expect RType be %v, got %v", reflect.TypeFor[Salary](), fr.RType)
+		}
+		switch v := val.(type) {
+		case int64:
+			return v + 1, nil
+		case float64:
+			return v * 2, nil
+		}
+		return val, nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	want := map[string]any{
+		"Salary":    int64(52480),
+		"Ratios[0]": float64(0.5),
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	if val.Salary != 52481 || val.Ratios[0] != 1 || val.Email != "email@example.com" {
+		t.Fatalf("expect only scalar values be replaced, got %+v", val)
+	}
+
+	// string replace functions skip scalar values
+	if err := s.Replace(func(fr FieldReplace, val string) (string, error) {
+		if fr.Name != "Email" {
+			t.Fatalf("unexpected field %s", fr.Path)
+		}
+		return val, nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.ReplaceScalarContext(ctx, func(ctx context.Context, fr FieldReplace, val any) (any, error) {
+		return val, nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect err is %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestCheck_ScalarOptions(t *testing.T) {
+	type tc struct {
+		val any
+		ok  bool
+	}
+
+	tcs := []tc{
+		{
+			val: struct {
+				Age int       `sensitive:"data,bucket=10"`
+				Tip *float32  `sensitive:"data,round=0.5"`
+				At  time.Time `sensitive:"data,truncate=day"`
+			}{},
+			ok: true,
+		},
+		{
+			val: struct {
+				Age int `sensitive:"data,bucket=abc"`
+			}{},
+		},
+		{
+			val: struct {
+				Age []int `sensitive:"data,round=0"`
+			}{},
+		},
+		{
+			val: struct {
+				Age int `sensitive:"data,bucket="`
+			}{},
+		},
+		{
+			val: struct {
+				Name string `sensitive:"data,bucket=10"`
+			}{},
+		},
+		{
+			val: struct {
+				At time.Time `sensitive:"data,round=10"`
+			}{},
+		},
+		{
+			val: struct {
+				Age int `sensitive:"data,truncate=year"`
+			}{},
+		},
+		{
+			val: struct {
+				At time.Time `sensitive:"data,truncate=week"`
+			}{},
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			// zero values are checked as well, since options are validated at scan time.
+			_, err := Check(tc.val)
+			if tc.ok {
+				if err != nil {
+					t.Fatal("expect err be nil, got", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidTagConfiguration) {
+				t.Fatalf("expect err is %v, got %v", ErrInvalidTagConfiguration, err)
+			}
+		})
+	}
+}
+
+func TestRedact_Scalar_Overflow(t *testing.T) {
+	type T struct {
+		Score int8 `sensitive:"data,round=10"`
+		Age   int  `sensitive:"data,bucket=10"`
+		Tail  int  `sensitive:"data"`
+	}
+
+	for _, continueOnError := range []bool{false, true} {
+		val := &T{Score: 127, Age: 42, Tail: 7}
+		err := Redact(val, func(rc *RedactConfig) {
+			rc.ContinueOnError = continueOnError
+		})
+		var fieldErr *FieldError
+		if !errors.Is(err, ErrInvalidScalarValue) || !errors.As(err, &fieldErr) {
+			t.Fatalf("expect err be a field error of %v, got %v", ErrInvalidScalarValue, err)
+		}
+		if fieldErr.Path != "Score" {
+			t.Fatalf("unexpected field error %+v", fieldErr)
+		}
+		// the overflowing value is zeroed, and the remaining fields are redacted.
+		if want := (&T{Age: 40}); !reflect.DeepEqual(want, val) {
+			t.Fatalf("want %+v, got %+v", want, val)
+		}
+	}
+}
@@ -1,25 +1,87 @@
 package sensitive
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"sync"
+
+	"github.com/ln80/struct-sensitive/internal/option"
 )
 
 var (
 	ErrInvalidTagConfiguration = errors.New("invalid 'sensitive' tag configuration")
 	ErrUnsupportedType         = errors.New("unsupported 'sensitive' type")
-	ErrUnsupportedFieldType    = errors.New("'sensitive' field type must be convertible to string")
+	ErrUnsupportedFieldType    = errors.New("unsupported 'sensitive' field type")
 	ErrMultipleNestedSubjectID = errors.New("potential multiple nested subject IDs")
 	ErrSubjectIDNotFound       = errors.New("subject ID is not found")
+	ErrMapKeyCollision         = errors.New("sensitive map keys collide after replacement")
+	ErrReferenceCycle          = errors.New("reference cycle found in sensitive value")
+
+	// SkipAll is used as a return value from [WalkFunc] to indicate that
+	// all the remaining sensitive fields are to be skipped. It is not returned as an error by [Struct.Walk].
+	SkipAll = errors.New("skip all sensitive fields")
 )
 
 // Struct provides an accessor for sensitive struct fields and subject identifiers.
 type Struct interface {
 	// Replace accepts a replacement function and applies it to each sensitive data field.
+	//
+	// Map keys that collide after replacement are dropped along with their values, and reported once all
+	// the fields are replaced using a [FieldError] that wraps [ErrMapKeyCollision]. Likewise, text values
+	// that reject their replacement are set to their zero value and reported using a [FieldError]
+	// that wraps [ErrInvalidTextValue].
 	Replace(fn ReplaceFunc) error
 
+	// ReplaceContext is like Replace but passes the given context to the replacement function.
+	// It stops and returns the context error as soon as the context is done.
+	ReplaceContext(ctx context.Context, fn ReplaceFuncCtx) error
+
+	// ReplaceScalar accepts a replacement function and applies it to each sensitive data field of a scalar type,
+	// i.e. numbers, booleans and [time.Time]. Note that Replace, Walk and Collect only process string and text values.
+	//
+	// Values that can't hold their replacement, e.g. an int8 replaced with 130, are set to their zero value
+	// and reported once all the fields are replaced using a [FieldError] that wraps [ErrInvalidScalarValue].
+	ReplaceScalar(fn ScalarReplaceFunc) error
+
+	// ReplaceScalarContext is like ReplaceScalar but passes the given context to the replacement function.
+	ReplaceScalarContext(ctx context.Context, fn ScalarReplaceFuncCtx) error
+
+	// ReplaceBytes accepts a replacement function and applies it to each sensitive data field of a bytes type,
+	// e.g. []byte, [json.RawMessage] or [database/sql.RawBytes].
+	ReplaceBytes(fn BytesReplaceFunc) error
+
+	// ReplaceBytesContext is like ReplaceBytes but passes the given context to the replacement function.
+	ReplaceBytesContext(ctx context.Context, fn BytesReplaceFuncCtx) error
+
+	// Walk visits each sensitive data field in read-only mode and calls the given function with its value.
+	// Unlike Replace, it also visits unaddressable values, such as struct values accessed via [Inspect]
+	// or structs held by maps.
+	//
+	// The walk stops at the first error returned by the function; use [SkipAll] to stop it without error.
+	//
+	// Only string values, text values (see [FieldReplace.IsText]) and map keys are visited, including the values
+	// of [Redactable] or [Maskable] string types, e.g. `type Phone string`; scalar, bytes and the other
+	// Redactable or Maskable data fields are skipped. Use WalkValues to visit them as well.
+	Walk(fn WalkFunc) error
+
+	// WalkValues is like Walk, but it visits every sensitive data field, including scalar, bytes and
+	// [Redactable] or [Maskable] ones, and calls the given function with its typed value (see [WalkValueFunc]).
+	WalkValues(fn WalkValueFunc) error
+
+	// Collect returns the values of all sensitive string and text data fields and map keys along with their metadata,
+	// in the same order they are visited by Replace. Like Walk, it skips the other data fields; Redactable or
+	// Maskable string types are collected as well, since Replace and Apply replace them as any string.
+	//
+	// Collect and Apply allow to replace sensitive values in two phases,
+	// e.g. to serve all the fields using a single call to a remote service.
+	Collect() ([]FieldValue, error)
+
+	// Apply replaces the sensitive values identified by their path (see [FieldReplace.Path])
+	// with the given ones. Sensitive values whose path is not found in the map are left untouched.
+	Apply(values map[string]string) error
+
 	// SubjectID returns the resolved SubjectID of the sensitive struct.
 	// It panics if the SubjectID is not resolved.
 	SubjectID() string
@@ -27,6 +89,15 @@ type Struct interface {
 	// HasSensitive indicates whether the struct contains sensitive data fields.
 	HasSensitive() bool
 
+	// replaceHooks applies the given function to each sensitive data field that implements [Redactable] or [Maskable].
+	replaceHooks(ctx context.Context, fn hookReplaceFuncCtx) error
+
+	// replaceContext is like ReplaceContext, but it returns the field errors that did not stop the replacement separately.
+	replaceContext(ctx context.Context, fn ReplaceFuncCtx) (fieldErrs []error, err error)
+
+	// replaceScalarContext is like ReplaceScalarContext, but it returns the field errors that did not stop the replacement separately.
+	replaceScalarContext(ctx context.Context, fn ScalarReplaceFuncCtx) (fieldErrs []error, err error)
+
 	private()
 }
 
@@ -39,10 +110,32 @@ type FieldReplace struct {
 	// Name is the name of the sensitive field.
 	Name string
 
+	// Path is the full path of the sensitive value from the root struct,
+	// e.g. `Orders[2].Shipping.Address.Street`.
+	// Elements of collections are identified by their index or map key.
+	// Map keys are identified by the '#key' suffix, e.g. `Contacts[eric@example.com]#key`.
+	Path string
+
+	// Parent is the type of the struct that holds the sensitive field.
+	Parent reflect.Type
+
+	// Field is the struct field definition of the sensitive field.
+	Field reflect.StructField
+
 	// RType is the original type of the sensitive field.
-	// Note that this type must be convertible to a string.
+	// Note that values of string types are converted to strings, and text types are marshaled to text (see [FieldReplace.IsText]).
+	//
+	// For collections of strings, RType is the type of the collection element.
 	RType reflect.Type
 
+	// Key is the index (int) or the map key of the element being replaced
+	// when the sensitive field is a collection (slice, array or map); it is nil otherwise.
+	Key any
+
+	// MapKey indicates that the value being replaced is a map key rather than a field value.
+	// Keys are replaced only if the 'keys' option is specified in the 'sensitive' tag.
+	MapKey bool
+
 	// Kind is the user-defined type of sensitive data, defined as an option in the 'sensitive' tag.
 	Kind string
 
@@ -50,17 +143,70 @@ type FieldReplace struct {
 	Options TagOptions
 }
 
+// FieldValue is a sensitive value returned by [Struct.Collect] along with its metadata.
+type FieldValue struct {
+	FieldReplace
+
+	// Value is the value of the sensitive field converted to a string.
+	Value string
+}
+
 // ReplaceFunc is a callback function executed by the [Struct.Replace] method.
 // It receives the original value of the sensitive field, converted to a string,
 // and returns the new value as a string along with any error that may occur.
 type ReplaceFunc func(fr FieldReplace, val string) (string, error)
 
+// ReplaceFuncCtx is the context-aware variant of [ReplaceFunc] executed by the [Struct.ReplaceContext] method.
+type ReplaceFuncCtx func(ctx context.Context, fr FieldReplace, val string) (string, error)
+
+// WalkFunc is a callback function executed by the [Struct.Walk] method.
+// It receives the value of the sensitive field converted to a string.
+type WalkFunc func(fr FieldReplace, val string) error
+
+// WalkValueFunc is a callback function executed by the [Struct.WalkValues] method.
+// It receives the value of the sensitive field as:
+//
+//   - a string for string fields and map keys;
+//   - an int64, uint64, float64, bool or [time.Time] for scalar fields (see [ScalarReplaceFunc]);
+//   - a []byte for bytes fields;
+//   - the field value itself for the other fields, i.e. text and [Redactable] or [Maskable] fields.
+type WalkValueFunc func(fr FieldReplace, val any) error
+
+// ScanConfig presents the configuration of the [Struct] accessor returned by [Scan] and [Inspect].
+type ScanConfig struct {
+	// ErrorOnReferenceCycle makes the accessor methods fail with [ErrReferenceCycle]
+	// if a value references itself, e.g. a linked list loop.
+	// By default, references (pointers, slices and maps) are processed once,
+	// and the ones that create a loop are skipped.
+	ErrorOnReferenceCycle bool
+
+	// Strict makes the scan fail with [ErrUnsupportedFieldType] if a 'data' tag is set on a field
+	// whose type is not supported, or if a 'dive' tag is set on a field that does not hold structs.
+	// By default, such fields are ignored.
+	Strict bool
+}
+
 // Scan inspects the given value and returns an accessor for the sensitive struct.
 // It returns an error if the value is not a pointer to a struct or if the 'sensitive' tag is misconfigured.
 //
 // The [Struct] accessor and the [Scan] function are low-level components.
 // In most cases, you should consider using the [Redact] or [Mask] functions instead.
-func Scan(v any, requireSubject bool) (accessor Struct, err error) {
+func Scan(v any, requireSubject bool, opts ...func(*ScanConfig)) (accessor Struct, err error) {
+	return scan(v, requireSubject, false, opts)
+}
+
+// Inspect is like [Scan] but it also accepts struct values in addition to struct pointers.
+//
+// It is intended for read-only use cases through [Struct.Walk], since [Struct.Replace] has no effect
+// on the fields of struct values.
+func Inspect(v any, requireSubject bool, opts ...func(*ScanConfig)) (accessor Struct, err error) {
+	return scan(v, requireSubject, true, opts)
+}
+
+func scan(v any, requireSubject, acceptValue bool, opts []func(*ScanConfig)) (accessor Struct, err error) {
+	cfg := ScanConfig{}
+	option.Apply(&cfg, opts)
+
 	defer func() {
 		// normalize error
 		if err != nil && !errors.Is(err, ErrUnsupportedType) {
@@ -74,7 +220,7 @@ func Scan(v any, requireSubject bool) (accessor Struct, err error) {
 	}
 
 	tt := reflect.TypeOf(v)
-	if tt.Kind() != reflect.Pointer {
+	if tt.Kind() != reflect.Pointer && !acceptValue {
 		err = fmt.Errorf("%w '%v'", ErrUnsupportedType, tt)
 		return
 	}
@@ -91,6 +237,11 @@ func Scan(v any, requireSubject bool) (accessor Struct, err error) {
 	if err != nil {
 		return
 	}
+	if cfg.Strict {
+		if err = ssType.strictError(); err != nil {
+			return
+		}
+	}
 	if !ssType.hasSensitive {
 		// The struct does not contain sensitive data, so there is no need to resolve the subject ID value.
 		// Therefore, calling 'reflect.ValueOf' is unnecessary due to its associated cost.
@@ -100,9 +251,19 @@ func Scan(v any, requireSubject bool) (accessor Struct, err error) {
 		return
 	}
 
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			err = fmt.Errorf("%w nil '%v'", ErrUnsupportedType, val.Type())
+			return
+		}
+		val = val.Elem()
+	}
+
 	structValue := sensitiveStruct{
 		typ: ssType,
-		val: reflect.ValueOf(v).Elem(),
+		val: val,
+		cfg: cfg,
 	}
 
 	if requireSubject {
@@ -126,6 +287,9 @@ var (
 
 type sensitiveStructContext struct {
 	seen map[reflect.Type]*sensitiveStructType
+
+	// scanned are the struct types scanned by the current scan, including the nested ones.
+	scanned *[]reflect.Type
 }
 
 type sensitiveField struct {
@@ -133,6 +297,16 @@ type sensitiveField struct {
 	isSub, isData, isNested bool
 	prefix                  string
 	isSlice, isMap          bool
+	elemType                reflect.Type
+	hasKeys                 bool
+	keysKind                string
+	isDynamic               bool
+	isAuto                  bool
+	isScalar                bool
+	isBytes                 bool
+	isText                  bool
+	isHook                  bool
+	wrapper                 *wrapper
 	nestedStructType        *sensitiveStructType
 	nestedStructTypeRef     reflect.Type
 	kind                    string
@@ -156,12 +330,62 @@ type sensitiveStructType struct {
 	subField        sensitiveField
 	sensitiveFields []sensitiveField
 	rt              reflect.Type
+
+	// unsupportedFields are the tagged fields, including the nested ones, ignored due to their types.
+	unsupportedFields []unsupportedField
+}
+
+// strictError returns an error that reports the unsupported fields of the struct type, if any.
+func (t sensitiveStructType) strictError() error {
+	errs := make([]error, 0, len(t.unsupportedFields))
+	for _, f := range t.unsupportedFields {
+		errs = append(errs, fmt.Errorf("%w: '%s' field '%s' of type '%v'", ErrUnsupportedFieldType, f.tag, f.path, f.typ))
+	}
+	return errors.Join(errs...)
+}
+
+// unsupportedField describes a tagged field ignored because its type is not supported.
+type unsupportedField struct {
+	path string
+	tag  string
+	typ  reflect.Type
 }
 
 type sensitiveStruct struct {
 	typ       sensitiveStructType
 	val       reflect.Value
 	subjectID string
+
+	// path is the path of the struct value from the root struct; it is empty for the root struct.
+	path string
+
+	// readOnly indicates that the struct is walked without being updated.
+	readOnly bool
+
+	cfg ScanConfig
+
+	// visitor tracks the references visited during the traversal; it is shared with nested structs.
+	visitor *visitor
+
+	// scalarFn is the replace function of scalar data fields; string data fields are skipped if it is set.
+	scalarFn ScalarReplaceFuncCtx
+
+	// bytesFn is the replace function of bytes data fields; string data fields are skipped if it is set.
+	bytesFn BytesReplaceFuncCtx
+
+	// hookFn is the replace function of data fields that implement [Redactable] or [Maskable];
+	// the other data fields are skipped if it is set.
+	hookFn hookReplaceFuncCtx
+
+	// walkFn is the function called with the typed value of every data field walked by WalkValues;
+	// map keys are still passed to the string replace function.
+	walkFn WalkValueFunc
+}
+
+// isStringPass indicates that string and text data fields and map keys are being replaced,
+// rather than scalar, bytes or hook data fields.
+func (s sensitiveStruct) isStringPass() bool {
+	return s.scalarFn == nil && s.bytesFn == nil && s.hookFn == nil
 }
 
 func (ps sensitiveStruct) private() {}
@@ -172,8 +396,18 @@ var _ Struct = &sensitiveStruct{}
 // the struct and its nested sensitive structs.
 //
 // It returns an error if the subject ID is missing or duplicated.
-func resolveSubject(pt sensitiveStructType, pv reflect.Value) (string, error) {
+func resolveSubject(pt sensitiveStructType, pv reflect.Value, vs *visitor) (string, error) {
 	subject := ""
+	pv = reflect.Indirect(pv)
+	if !pv.IsValid() {
+		return "", fmt.Errorf("%w in '%v'", ErrSubjectIDNotFound, pt.rt)
+	}
+	if pv.CanAddr() {
+		if ok, _ := vs.enter(pv.Addr()); !ok {
+			return "", fmt.Errorf("%w in '%v'", ErrSubjectIDNotFound, pt.rt)
+		}
+		defer vs.leave(pv.Addr())
+	}
 	if !pt.subField.IsZero() {
 		subject = pt.subField.prefix + reflect.Indirect(pv.FieldByIndex(pt.subField.sf.Index)).String()
 	}
@@ -187,31 +421,55 @@ func resolveSubject(pt sensitiveStructType, pv reflect.Value) (string, error) {
 		if sensitiveFieldV.IsZero() {
 			continue
 		}
+		if ssField.isSlice || ssField.isMap {
+			// nested struct pointers are tracked by the recursive call.
+			coll := reflect.Indirect(sensitiveFieldV)
+			if ok, _ := vs.enter(coll); !ok {
+				continue
+			}
+			defer vs.leave(coll)
+		}
+
+		var ssTv sensitiveStructType
+		if !ssField.isDynamic {
+			cacheMu.Lock()
+			ssT := ssField.getType(cache)
+			cacheMu.Unlock()
+			// I believe ssT can't be nil
+			ssTv = *ssT
+		}
+		resolve := func(v reflect.Value) string {
+			if ssField.isDynamic {
+				ssT, dyn, ok, _ := dynamicStruct(v)
+				if !ok {
+					return ""
+				}
+				subject, _ := resolveSubject(ssT, dyn, vs)
+				return subject
+			}
+			subject, _ := resolveSubject(ssTv, v, vs)
+			return subject
+		}
 
-		cacheMu.Lock()
-		ssT := ssField.getType(cache)
-		cacheMu.Unlock()
-		// I believe ssT can't be nil
-		ssTv := *ssT
 		sensitiveFieldV = reflect.Indirect(sensitiveFieldV)
 		nestedSubject := ""
 		switch {
 		case ssField.isSlice:
 			for i := 0; i < sensitiveFieldV.Len(); i++ {
-				nestedSubject, _ = resolveSubject(ssTv, sensitiveFieldV.Index(i))
+				nestedSubject = resolve(sensitiveFieldV.Index(i))
 				if nestedSubject != "" {
 					break
 				}
 			}
 		case ssField.isMap:
 			for _, k := range sensitiveFieldV.MapKeys() {
-				nestedSubject, _ = resolveSubject(ssTv, sensitiveFieldV.MapIndex(k))
+				nestedSubject = resolve(sensitiveFieldV.MapIndex(k))
 				if nestedSubject != "" {
 					break
 				}
 			}
 		default:
-			nestedSubject, _ = resolveSubject(ssTv, sensitiveFieldV)
+			nestedSubject = resolve(sensitiveFieldV)
 		}
 
 		if nestedSubject != "" {
@@ -231,7 +489,7 @@ func resolveSubject(pt sensitiveStructType, pv reflect.Value) (string, error) {
 func (ps *sensitiveStruct) resolveSubject() (string, error) {
 	if ps.subjectID == "" {
 		var err error
-		ps.subjectID, err = resolveSubject(ps.typ, ps.val)
+		ps.subjectID, err = resolveSubject(ps.typ, ps.val, newVisitor(false))
 		if err != nil {
 			return "", err
 		}
@@ -251,109 +509,676 @@ func (ss sensitiveStruct) HasSensitive() bool {
 	return ss.typ.hasSensitive
 }
 
+// replaceData applies the replace function to the given sensitive data value.
+// The value is either a settable string, scalar, bytes, text or hook value, a wrapper of one of them (see [RegisterWrapper]),
+// or a pointer to one of them.
+func (s sensitiveStruct) replaceData(ctx context.Context, ssField sensitiveField, v reflect.Value, key any, fn ReplaceFuncCtx) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if v.IsZero() {
+		return nil
+	}
+	if ok, err := s.visitor.enter(v); !ok {
+		return err
+	}
+	defer s.visitor.leave(v)
+	elem := reflect.Indirect(v)
+
+	rType := ssField.sf.Type
+	if ssField.elemType != nil {
+		rType = ssField.elemType
+	}
+	if ssField.wrapper != nil {
+		// the wrapped value is replaced, provided that the wrapper is valid.
+		var ok bool
+		if elem, ok = ssField.wrapper.unwrap(elem); !ok {
+			return nil
+		}
+		rType = ssField.wrapper.valueType
+	}
+
+	path := s.fieldPath(ssField)
+	if key != nil {
+		path = indexPath(path, key)
+	}
+
+	fr := FieldReplace{
+		SubjectID: s.subjectID,
+		Name:      ssField.sf.Name,
+		Path:      path,
+		Parent:    s.typ.rt,
+		Field:     ssField.sf,
+		RType:     rType,
+		Key:       key,
+		Kind:      ssField.kind,
+		Options:   ssField.options,
+	}
+
+	if s.walkFn != nil {
+		switch {
+		case ssField.isScalar:
+			return s.walkFn(fr, scalarValue(elem))
+		case ssField.isBytes:
+			return s.walkFn(fr, elem.Bytes())
+		case elem.Kind() == reflect.String && !ssField.isText:
+			return s.walkFn(fr, elem.String())
+		default:
+			return s.walkFn(fr, elem.Interface())
+		}
+	}
+
+	if s.hookFn != nil {
+		if !ssField.isHook {
+			return nil
+		}
+		return s.hookFn(ctx, fr, elem)
+	}
+
+	switch {
+	case ssField.isScalar:
+		if s.scalarFn == nil {
+			return nil
+		}
+		newVal, err := s.scalarFn(ctx, fr, scalarValue(elem))
+		if err != nil {
+			return err
+		}
+		if err := setScalarValue(elem, newVal); err != nil {
+			// the rejected value is zeroed rather than left in cleartext, and the traversal goes on.
+			elem.SetZero()
+			s.visitor.fieldErrs = append(s.visitor.fieldErrs, &FieldError{Path: fr.Path, Kind: fr.Kind, Err: err})
+		}
+		return nil
+
+	case ssField.isBytes:
+		if s.bytesFn == nil || elem.Len() == 0 {
+			return nil
+		}
+		newVal, err := s.bytesFn(ctx, fr, elem.Bytes())
+		if err != nil {
+			return err
+		}
+		elem.SetBytes(newVal)
+		return nil
+
+	case !s.isStringPass():
+		return nil
+
+	case ssField.isText:
+		val, err := textValue(elem)
+		if err != nil {
+			return err
+		}
+		newVal, err := fn(ctx, fr, val)
+		if err != nil {
+			return err
+		}
+		if newVal == val || s.readOnly {
+			return nil
+		}
+		if err := setTextValue(elem, newVal); err != nil {
+			// the rejected value is zeroed rather than left in cleartext, and the traversal goes on.
+			elem.SetZero()
+			s.visitor.fieldErrs = append(s.visitor.fieldErrs, &FieldError{Path: fr.Path, Kind: fr.Kind, Err: err})
+		}
+		return nil
+
+	case elem.Kind() != reflect.String:
+		// values that only implement Redactable or Maskable are replaced by their own methods.
+		return nil
+	}
+
+	val := elem.String()
+	newVal, err := fn(ctx, fr, val)
+	if err != nil {
+		return err
+	}
+	if newVal != val {
+		elem.SetString(newVal)
+	}
+	return nil
+}
+
+func (s sensitiveStruct) Walk(fn WalkFunc) error {
+	s.readOnly = true
+	s.visitor = newVisitor(s.cfg.ErrorOnReferenceCycle)
+	err := s.replace(context.Background(), func(_ context.Context, fr FieldReplace, val string) (string, error) {
+		return val, fn(fr, val)
+	})
+	if errors.Is(err, SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func (s sensitiveStruct) WalkValues(fn WalkValueFunc) error {
+	s.readOnly = true
+	s.visitor = newVisitor(s.cfg.ErrorOnReferenceCycle)
+	s.walkFn = fn
+	err := s.replace(context.Background(), func(_ context.Context, fr FieldReplace, val string) (string, error) {
+		return val, fn(fr, val)
+	})
+	if errors.Is(err, SkipAll) {
+		return nil
+	}
+	return err
+}
+
 func (s sensitiveStruct) Replace(fn ReplaceFunc) error {
-	var (
-		newVal string
-		err    error
-	)
+	return s.ReplaceContext(context.Background(), func(_ context.Context, fr FieldReplace, val string) (string, error) {
+		return fn(fr, val)
+	})
+}
+
+func (s sensitiveStruct) ReplaceContext(ctx context.Context, fn ReplaceFuncCtx) error {
+	fieldErrs, err := s.replaceContext(ctx, fn)
+	return errors.Join(append([]error{err}, fieldErrs...)...)
+}
+
+// replaceContext is like ReplaceContext, but it returns the field errors that did not stop the replacement
+// (see [ErrMapKeyCollision]) separately from the error that stopped it, if any.
+func (s sensitiveStruct) replaceContext(ctx context.Context, fn ReplaceFuncCtx) (fieldErrs []error, err error) {
+	s.visitor = newVisitor(s.cfg.ErrorOnReferenceCycle)
+	err = s.replace(ctx, fn)
+	return s.visitor.fieldErrs, err
+}
+
+func (s sensitiveStruct) ReplaceScalar(fn ScalarReplaceFunc) error {
+	return s.ReplaceScalarContext(context.Background(), func(_ context.Context, fr FieldReplace, val any) (any, error) {
+		return fn(fr, val)
+	})
+}
+
+func (s sensitiveStruct) ReplaceScalarContext(ctx context.Context, fn ScalarReplaceFuncCtx) error {
+	fieldErrs, err := s.replaceScalarContext(ctx, fn)
+	return errors.Join(append([]error{err}, fieldErrs...)...)
+}
+
+// replaceScalarContext is like ReplaceScalarContext, but it returns the field errors that did not stop the replacement
+// (see [ErrInvalidScalarValue]) separately from the error that stopped it, if any.
+func (s sensitiveStruct) replaceScalarContext(ctx context.Context, fn ScalarReplaceFuncCtx) (fieldErrs []error, err error) {
+	s.visitor = newVisitor(s.cfg.ErrorOnReferenceCycle)
+	s.scalarFn = fn
+	err = s.replace(ctx, nil)
+	return s.visitor.fieldErrs, err
+}
+
+func (s sensitiveStruct) ReplaceBytes(fn BytesReplaceFunc) error {
+	return s.ReplaceBytesContext(context.Background(), func(_ context.Context, fr FieldReplace, val []byte) ([]byte, error) {
+		return fn(fr, val)
+	})
+}
+
+func (s sensitiveStruct) ReplaceBytesContext(ctx context.Context, fn BytesReplaceFuncCtx) error {
+	s.visitor = newVisitor(s.cfg.ErrorOnReferenceCycle)
+	s.bytesFn = fn
+	return s.replace(ctx, nil)
+}
+
+func (s sensitiveStruct) Collect() ([]FieldValue, error) {
+	var values []FieldValue
+	if err := s.Walk(func(fr FieldReplace, val string) error {
+		values = append(values, FieldValue{FieldReplace: fr, Value: val})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (s sensitiveStruct) Apply(values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return s.Replace(func(fr FieldReplace, val string) (string, error) {
+		if newVal, ok := values[fr.Path]; ok {
+			return newVal, nil
+		}
+		return val, nil
+	})
+}
+
+func (s sensitiveStruct) replace(ctx context.Context, fn ReplaceFuncCtx) error {
+	if s.val.CanAddr() {
+		if ok, err := s.visitor.enter(s.val.Addr()); !ok {
+			return err
+		}
+		defer s.visitor.leave(s.val.Addr())
+	}
+
 	for _, ssField := range s.typ.sensitiveFields {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		v := s.val.FieldByIndex(ssField.sf.Index)
 
 		if v.IsZero() {
 			continue
 		}
 
-		if !v.CanSet() {
+		if !v.CanSet() && !s.readOnly {
 			continue
 		}
-		elem := reflect.Indirect(v)
 
-		if ssField.isData {
-			val := elem.String()
+		if err := s.replaceField(ctx, ssField, v, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-			newVal, err = fn(FieldReplace{
-				SubjectID: s.subjectID,
-				RType:     ssField.sf.Type,
-				Kind:      ssField.kind,
-				Options:   ssField.options,
-			}, val)
-			if err != nil {
+func (s sensitiveStruct) replaceField(ctx context.Context, ssField sensitiveField, v reflect.Value, fn ReplaceFuncCtx) error {
+	elem := reflect.Indirect(v)
+	if ssField.isSlice || ssField.isMap {
+		// collections are tracked as well, e.g. a map shared by several structs or holding its parent.
+		if ok, err := s.visitor.enter(elem); !ok {
+			return err
+		}
+		defer s.visitor.leave(elem)
+	}
+
+	var err error
+	switch {
+	case ssField.isData:
+		err = s.replaceDataField(ctx, ssField, v, elem, fn)
+	case ssField.isNested:
+		err = s.replaceNestedField(ctx, ssField, elem, fn)
+	}
+	if err != nil {
+		return err
+	}
+
+	if ssField.hasKeys && s.isStringPass() {
+		return s.replaceKeys(ctx, ssField, elem, fn)
+	}
+	return nil
+}
+
+func (s sensitiveStruct) replaceDataField(ctx context.Context, ssField sensitiveField, v, elem reflect.Value, fn ReplaceFuncCtx) error {
+	switch {
+	case ssField.isSlice:
+		for i := 0; i < elem.Len(); i++ {
+			if err := s.replaceData(ctx, ssField, elem.Index(i), i, fn); err != nil {
 				return err
 			}
-			if newVal != val {
-				elem.SetString(newVal)
+		}
+
+	case ssField.isMap:
+		for _, k := range elem.MapKeys() {
+			mapElem := elem.MapIndex(k)
+			if mapElem.IsZero() {
+				continue
+			}
+			if mapElem.Kind() == reflect.Pointer {
+				if err := s.replaceData(ctx, ssField, mapElem, k.Interface(), fn); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// map values are not addressable, replace a copy then put it back.
+			newElem := reflect.New(mapElem.Type()).Elem()
+			newElem.Set(mapElem)
+			if err := s.replaceData(ctx, ssField, newElem, k.Interface(), fn); err != nil {
+				return err
+			}
+			// bytes values are not comparable, hence the deep comparison.
+			if !reflect.DeepEqual(newElem.Interface(), mapElem.Interface()) {
+				elem.SetMapIndex(k, newElem)
 			}
-			continue
 		}
 
-		if ssField.isNested {
-			var ssT sensitiveStructType
+	default:
+		return s.replaceData(ctx, ssField, v, nil, fn)
+	}
+	return nil
+}
 
-			cacheMu.Lock()
-			ssTPtr := ssField.getType(cache)
-			cacheMu.Unlock()
+func (s sensitiveStruct) replaceNestedField(ctx context.Context, ssField sensitiveField, elem reflect.Value, fn ReplaceFuncCtx) error {
+	if ssField.isDynamic {
+		return s.replaceDynamicField(ctx, ssField, elem, fn)
+	}
+
+	var ssT sensitiveStructType
+
+	cacheMu.Lock()
+	ssTPtr := ssField.getType(cache)
+	cacheMu.Unlock()
+
+	// I believe ssTPtr can't be nil
+	ssT = *ssTPtr
+	if !ssT.hasSensitive {
+		return nil
+	}
 
-			// I believe ssTPtr can't be nil
-			ssT = *ssTPtr
-			if !ssT.hasSensitive {
+	path := s.fieldPath(ssField)
+
+	switch {
+	case ssField.isSlice:
+		for i := 0; i < elem.Len(); i++ {
+			if elem.Index(i).IsZero() {
 				continue
 			}
+			if err := (&sensitiveStruct{
+				subjectID: s.subjectID, // inherit parent subject ID
+				val:       reflect.Indirect(elem.Index(i)),
+				typ:       ssT,
+				path:      indexPath(path, i),
+				visitor:   s.visitor,
+				scalarFn:  s.scalarFn,
+				bytesFn:   s.bytesFn,
+				hookFn:    s.hookFn,
+				walkFn:    s.walkFn,
+				readOnly:  s.readOnly,
+			}).replace(ctx, fn); err != nil {
+				return err
+			}
+		}
 
-			switch {
-			case ssField.isSlice:
-				for i := 0; i < elem.Len(); i++ {
-					if err := (&sensitiveStruct{
-						subjectID: s.subjectID, // inherit parent subject ID
-						val:       reflect.Indirect(elem.Index(i)),
-						typ:       ssT,
-					}).Replace(fn); err != nil {
-						return err
-					}
-				}
+	case ssField.isMap:
+		for _, k := range elem.MapKeys() {
+			mapElem := elem.MapIndex(k)
+			if mapElem.IsZero() {
+				continue
+			}
+			mapElem = reflect.Indirect(elem.MapIndex(k))
+			if !mapElem.CanAddr() && !s.readOnly {
+				newElem := reflect.New(mapElem.Type()).Elem()
+				newElem.Set(mapElem)
 
-			case ssField.isMap:
-				for _, k := range elem.MapKeys() {
-					mapElem := elem.MapIndex(k)
-					if mapElem.IsZero() {
-						continue
-					}
-					mapElem = reflect.Indirect(elem.MapIndex(k))
-					if !mapElem.CanAddr() {
-						newElem := reflect.New(mapElem.Type()).Elem()
-						newElem.Set(mapElem)
-
-						if err := (&sensitiveStruct{
-							subjectID: s.subjectID, // inherit parent subject ID
-							val:       newElem,
-							typ:       ssT,
-						}).Replace(fn); err != nil {
-							return err
-						}
-
-						elem.SetMapIndex(k, newElem)
-						continue
-					}
-
-					if err := (&sensitiveStruct{
-						subjectID: s.subjectID,
-						val:       reflect.Indirect(elem.MapIndex(k)),
-						typ:       ssT,
-					}).Replace(fn); err != nil {
-						return err
-					}
-				}
-			default:
 				if err := (&sensitiveStruct{
-					subjectID: s.subjectID,
-					val:       elem,
+					subjectID: s.subjectID, // inherit parent subject ID
+					val:       newElem,
 					typ:       ssT,
-				}).Replace(fn); err != nil {
+					path:      indexPath(path, k.Interface()),
+					visitor:   s.visitor,
+					scalarFn:  s.scalarFn,
+					bytesFn:   s.bytesFn,
+					hookFn:    s.hookFn,
+				}).replace(ctx, fn); err != nil {
 					return err
 				}
+
+				elem.SetMapIndex(k, newElem)
+				continue
+			}
+
+			if err := (&sensitiveStruct{
+				subjectID: s.subjectID,
+				val:       reflect.Indirect(elem.MapIndex(k)),
+				typ:       ssT,
+				path:      indexPath(path, k.Interface()),
+				visitor:   s.visitor,
+				scalarFn:  s.scalarFn,
+				bytesFn:   s.bytesFn,
+				hookFn:    s.hookFn,
+				walkFn:    s.walkFn,
+				readOnly:  s.readOnly,
+			}).replace(ctx, fn); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := (&sensitiveStruct{
+			subjectID: s.subjectID,
+			val:       elem,
+			typ:       ssT,
+			path:      path,
+			visitor:   s.visitor,
+			scalarFn:  s.scalarFn,
+			bytesFn:   s.bytesFn,
+			hookFn:    s.hookFn,
+			walkFn:    s.walkFn,
+			readOnly:  s.readOnly,
+		}).replace(ctx, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dynamicStruct returns the sensitive struct type and the struct value held by the given interface value.
+// It returns false if the interface value does not hold a struct or a struct pointer with sensitive fields.
+func dynamicStruct(v reflect.Value) (sensitiveStructType, reflect.Value, bool, error) {
+	if v.Kind() != reflect.Interface || v.IsNil() {
+		return sensitiveStructType{}, reflect.Value{}, false, nil
+	}
+	dyn := reflect.Indirect(v.Elem())
+	if !dyn.IsValid() || dyn.Kind() != reflect.Struct {
+		return sensitiveStructType{}, reflect.Value{}, false, nil
+	}
+	ssT, err := scanStructType(dyn.Type())
+	if err != nil {
+		return sensitiveStructType{}, reflect.Value{}, false, errors.Join(ErrInvalidTagConfiguration, err)
+	}
+	return ssT, dyn, ssT.hasSensitive, nil
+}
+
+// replaceDynamicField applies the replace function to the structs held by an interface field,
+// or by a collection of interfaces.
+func (s sensitiveStruct) replaceDynamicField(ctx context.Context, ssField sensitiveField, elem reflect.Value, fn ReplaceFuncCtx) error {
+	path := s.fieldPath(ssField)
+
+	switch {
+	case ssField.isSlice:
+		for i := 0; i < elem.Len(); i++ {
+			newElem, err := s.replaceDynamic(ctx, elem.Index(i), indexPath(path, i), fn)
+			if err != nil {
+				return err
 			}
+			if newElem.IsValid() {
+				elem.Index(i).Set(newElem)
+			}
+		}
+
+	case ssField.isMap:
+		for _, k := range elem.MapKeys() {
+			newElem, err := s.replaceDynamic(ctx, elem.MapIndex(k), indexPath(path, k.Interface()), fn)
+			if err != nil {
+				return err
+			}
+			if newElem.IsValid() {
+				elem.SetMapIndex(k, newElem)
+			}
+		}
+
+	default:
+		newElem, err := s.replaceDynamic(ctx, elem, path, fn)
+		if err != nil {
+			return err
+		}
+		if newElem.IsValid() {
+			elem.Set(newElem)
+		}
+	}
+	return nil
+}
+
+// replaceDynamic applies the replace function to the struct held by the given interface value.
+//
+// Structs held by value are not addressable, so a copy is replaced instead; the copy is returned
+// to be set back in place of the interface value.
+func (s sensitiveStruct) replaceDynamic(ctx context.Context, v reflect.Value, path string, fn ReplaceFuncCtx) (reflect.Value, error) {
+	ssT, dyn, ok, err := dynamicStruct(v)
+	if !ok {
+		return reflect.Value{}, err
+	}
+
+	child := &sensitiveStruct{
+		subjectID: s.subjectID,
+		val:       dyn,
+		typ:       ssT,
+		path:      path,
+		visitor:   s.visitor,
+		scalarFn:  s.scalarFn,
+		bytesFn:   s.bytesFn,
+		hookFn:    s.hookFn,
+		walkFn:    s.walkFn,
+		readOnly:  s.readOnly,
+	}
+	if dyn.CanAddr() || s.readOnly {
+		return reflect.Value{}, child.replace(ctx, fn)
+	}
+
+	child.val = reflect.New(dyn.Type()).Elem()
+	child.val.Set(dyn)
+	if err := child.replace(ctx, fn); err != nil {
+		return reflect.Value{}, err
+	}
+	return child.val, nil
+}
+
+// visitKey identifies a reference (pointer, slice or map) visited during a traversal.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// visitor tracks the references visited during a traversal, so that shared references
+// are processed once and reference cycles do not cause an infinite recursion.
+type visitor struct {
+	errorOnCycle bool
+
+	// visited maps the visited references to whether they are being processed,
+	// i.e. they belong to the current traversal path.
+	visited map[visitKey]bool
+
+	// fieldErrs are the [FieldError]s that do not stop the traversal, e.g. map key collisions.
+	fieldErrs []error
+}
+
+func newVisitor(errorOnCycle bool) *visitor {
+	return &visitor{
+		errorOnCycle: errorOnCycle,
+		visited:      make(map[visitKey]bool),
+	}
+}
+
+func refKey(v reflect.Value) (visitKey, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map:
+		return visitKey{ptr: v.Pointer(), typ: v.Type()}, !v.IsNil()
+	case reflect.Slice:
+		return visitKey{ptr: v.Pointer(), typ: v.Type(), len: v.Len()}, !v.IsNil()
+	default:
+		return visitKey{}, false
+	}
+}
+
+// enter reports whether the given value must be processed. Values that are not references are always processed,
+// while references are processed once. It returns an error if the reference belongs to the current traversal path,
+// and the visitor is configured to fail on reference cycles.
+func (vs *visitor) enter(v reflect.Value) (bool, error) {
+	key, ok := refKey(v)
+	if !ok {
+		return true, nil
+	}
+	if processing, visited := vs.visited[key]; visited {
+		if processing && vs.errorOnCycle {
+			return false, fmt.Errorf("%w at '%v'", ErrReferenceCycle, v.Type())
+		}
+		return false, nil
+	}
+	vs.visited[key] = true
+	return true, nil
+}
+
+// leave marks the given reference as processed.
+func (vs *visitor) leave(v reflect.Value) {
+	if key, ok := refKey(v); ok {
+		vs.visited[key] = false
+	}
+}
+
+// keyPathSuffix identifies the path of a map key.
+const keyPathSuffix = "#key"
+
+// fieldPath returns the path of the given field of the struct.
+func (s sensitiveStruct) fieldPath(ssField sensitiveField) string {
+	if s.path == "" {
+		return ssField.sf.Name
+	}
+	return s.path + "." + ssField.sf.Name
+}
+
+// indexPath returns the path of an element of the collection identified by the given path.
+func indexPath(path string, key any) string {
+	return fmt.Sprintf("%s[%v]", path, key)
+}
+
+// replaceKeys applies the replace function to the keys of a sensitive map field.
+//
+// The map is updated in place once all keys are replaced. Entries whose keys collide after replacement
+// are dropped, and the collision is recorded as a [FieldError] wrapping [ErrMapKeyCollision] in the visitor,
+// so that the traversal goes on.
+func (s sensitiveStruct) replaceKeys(ctx context.Context, ssField sensitiveField, elem reflect.Value, fn ReplaceFuncCtx) error {
+	if elem.Len() == 0 {
+		return nil
+	}
+
+	path := s.fieldPath(ssField)
+	keys := elem.MapKeys()
+	newKeys := make([]reflect.Value, len(keys))
+	seen := make(map[string]int, len(keys))
+	changed := false
+	for i, k := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		val := k.String()
+		newVal, err := fn(ctx, FieldReplace{
+			SubjectID: s.subjectID,
+			Name:      ssField.sf.Name,
+			Path:      indexPath(path, k.Interface()) + keyPathSuffix,
+			Parent:    s.typ.rt,
+			Field:     ssField.sf,
+			RType:     k.Type(),
+			Key:       k.Interface(),
+			MapKey:    true,
+			Kind:      ssField.keysKind,
+			Options:   ssField.options,
+		}, val)
+		if err != nil {
+			return err
+		}
+		seen[newVal]++
+
+		newKeys[i] = k
+		if newVal != val {
+			newKeys[i] = reflect.New(k.Type()).Elem()
+			newKeys[i].SetString(newVal)
+			changed = true
 		}
 	}
+	if !changed {
+		return nil
+	}
 
+	values := make([]reflect.Value, len(keys))
+	for i, k := range keys {
+		values[i] = elem.MapIndex(k)
+	}
+	elem.Clear()
+	collision := false
+	for i, k := range newKeys {
+		// colliding entries are dropped, so that none of them keeps its original key or gets another one's value.
+		if seen[k.String()] > 1 {
+			collision = true
+			continue
+		}
+		elem.SetMapIndex(k, values[i])
+	}
+	if collision {
+		s.visitor.fieldErrs = append(s.visitor.fieldErrs, &FieldError{
+			Path: path + keyPathSuffix,
+			Kind: ssField.keysKind,
+			Err:  ErrMapKeyCollision,
+		})
+	}
 	return nil
 }
 
@@ -362,20 +1187,72 @@ func scanStructType(rt reflect.Type) (sensitiveStructType, error) {
 	defer cacheMu.Unlock()
 
 	if _, ok := cache[rt]; !ok {
-		c := sensitiveStructContext{seen: cache}
-		ssT, err := scanStructTypeWithContext(c, rt)
+		c := sensitiveStructContext{seen: cache, scanned: &[]reflect.Type{}}
+		// the type is registered before being scanned, so that it is not scanned again by its self-references.
+		ssT := &sensitiveStructType{}
+		cache[rt] = ssT
+		*c.scanned = append(*c.scanned, rt)
+
+		t, err := scanStructTypeWithContext(c, rt)
 		if err != nil {
+			for _, rt := range *c.scanned {
+				delete(cache, rt)
+			}
 			return sensitiveStructType{}, err
 		}
-		cache[rt] = &ssT
+		*ssT = t
+		c.resolveAutoDive()
 	}
 
 	return *cache[rt], nil
 }
 
+// resolveAutoDive marks the scanned struct types as sensitive if they auto dive into a sensitive struct type.
+//
+// It is called once the whole type graph is scanned, since auto dive fields may refer to types
+// whose scan was not complete yet, e.g. mutually referencing types.
+func (c sensitiveStructContext) resolveAutoDive() {
+	for changed := true; changed; {
+		changed = false
+		for _, rt := range *c.scanned {
+			ssT := c.seen[rt]
+			if ssT.hasSensitive {
+				continue
+			}
+			for _, ssField := range ssT.sensitiveFields {
+				if ssField.isAuto && ssField.getType(c.seen).hasSensitive {
+					ssT.hasSensitive = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
 func scanStructTypeWithContext(c sensitiveStructContext, rt reflect.Type) (sensitiveStructType, error) {
 	sensitiveFields := make([]sensitiveField, 0)
 	var subjectField sensitiveField
+	var unsupportedFields []unsupportedField
+	unsupported := func(field reflect.StructField, tag string) {
+		unsupportedFields = append(unsupportedFields, unsupportedField{
+			path: field.Name,
+			tag:  tag,
+			typ:  field.Type,
+		})
+	}
+	// nestedUnsupported reports the unsupported fields of the nested struct type of the given field.
+	nestedUnsupported := func(ssField sensitiveField) {
+		ssT := ssField.getType(c.seen)
+		if ssT == nil {
+			return
+		}
+		for _, f := range ssT.unsupportedFields {
+			f.path = ssField.sf.Name + "." + f.path
+			unsupportedFields = append(unsupportedFields, f)
+		}
+	}
+	autoDive := structAutoDive(rt)
 	for i := 0; i < rt.NumField(); i++ {
 		field := rt.Field(i)
 		if !field.IsExported() {
@@ -384,6 +1261,22 @@ func scanStructTypeWithContext(c sensitiveStructContext, rt reflect.Type) (sensi
 
 		tag, _ := extractTag(field.Tag)
 		if tag == "" {
+			if !isAutoDive(autoDive, field) {
+				continue
+			}
+			ssField := sensitiveField{
+				sf:       field,
+				isNested: true,
+				isAuto:   true,
+				options:  make(TagOptions),
+			}
+			if _, err := scanNestedField(c, &ssField); err != nil {
+				return sensitiveStructType{}, err
+			}
+			nestedUnsupported(ssField)
+			// Auto dive fields are kept even if their type is not sensitive, since the type might be still being scanned;
+			// they are skipped at replace time otherwise (see resolveAutoDive).
+			sensitiveFields = append(sensitiveFields, ssField)
 			continue
 		}
 		name, opts := parseTag(tag)
@@ -397,6 +1290,14 @@ func scanStructTypeWithContext(c sensitiveStructContext, rt reflect.Type) (sensi
 			options:  opts,
 		}
 
+		if _, ok := opts["keys"]; ok {
+			if err := validateKeysField(field); err != nil {
+				return sensitiveStructType{}, err
+			}
+			ssField.hasKeys = true
+			ssField.keysKind = opts["keys"]
+		}
+
 		switch {
 		case ssField.isSub:
 			if !field.Type.ConvertibleTo(stringType) {
@@ -413,52 +1314,137 @@ func scanStructTypeWithContext(c sensitiveStructContext, rt reflect.Type) (sensi
 			if tt.Kind() == reflect.Ptr {
 				tt = tt.Elem()
 			}
-			if tt.Kind() != reflect.String {
-				continue
+			switch {
+			case isBytesType(tt):
+				// bytes are not processed as a collection.
+			case tt.Kind() == reflect.Slice, tt.Kind() == reflect.Array:
+				ssField.isSlice = true
+			case tt.Kind() == reflect.Map:
+				ssField.isMap = true
 			}
-			sensitiveFields = append(sensitiveFields, ssField)
-
-		case ssField.isNested:
-			tt := field.Type
-			if tt.Kind() == reflect.Ptr {
+			if ssField.isSlice || ssField.isMap {
+				ssField.elemType = tt.Elem()
 				tt = tt.Elem()
+				if tt.Kind() == reflect.Ptr {
+					tt = tt.Elem()
+				}
 			}
-			if tt.Kind() == reflect.Slice {
-				ssField.isSlice = true
-				tt = tt.Elem()
+			if w, ok := wrapperOf(tt); ok {
+				ssField.wrapper = &w
+				tt = w.valueType
+				if tt.Kind() == reflect.Ptr {
+					tt = tt.Elem()
+				}
 			}
-			if tt.Kind() == reflect.Map {
-				ssField.isMap = true
-				tt = tt.Elem()
+			ssField.isScalar = isScalarType(tt)
+			ssField.isBytes = isBytesType(tt)
+			ssField.isText = isTextType(tt)
+			ssField.isHook = isHookType(tt)
+			if tt.Kind() != reflect.String && !ssField.isScalar && !ssField.isBytes && !ssField.isText && !ssField.isHook {
+				if !ssField.hasKeys {
+					unsupported(field, name)
+					continue
+				}
+				// Only the map keys are sensitive.
+				ssField.isData = false
 			}
-			if tt.Kind() == reflect.Ptr {
-				tt = tt.Elem()
+			if err := validateScalarOptions(field, tt, opts); err != nil {
+				return sensitiveStructType{}, err
 			}
+			sensitiveFields = append(sensitiveFields, ssField)
 
-			_, seen := c.seen[tt]
-			if !seen {
-				var ssType sensitiveStructType
-				var err error
-				c.seen[tt] = &ssType
-				ssType, err = scanStructTypeWithContext(c, tt)
-				if err != nil {
-					return sensitiveStructType{}, err
+		case ssField.isNested:
+			ok, err := scanNestedField(c, &ssField)
+			if err != nil {
+				return sensitiveStructType{}, err
+			}
+			if !ok {
+				if !ssField.hasKeys {
+					unsupported(field, name)
+					continue
 				}
-				ssField.nestedStructType = &ssType
-			} else {
-				ssField.nestedStructTypeRef = tt
+				// Only the map keys are sensitive.
+				ssField.isNested = false
 			}
-
+			nestedUnsupported(ssField)
 			sensitiveFields = append(sensitiveFields, ssField)
 		default:
 			return sensitiveStructType{}, fmt.Errorf("invalid tag name '%s'", name)
 		}
 	}
 
+	hasSensitive := false
+	for _, ssField := range sensitiveFields {
+		// Auto dive fields make the struct sensitive once the whole type graph is scanned (see resolveAutoDive).
+		if !ssField.isAuto {
+			hasSensitive = true
+			break
+		}
+	}
+
 	return sensitiveStructType{
-		hasSensitive:    len(sensitiveFields) > 0,
+		hasSensitive:    hasSensitive,
 		subField:        subjectField,
 		sensitiveFields: sensitiveFields,
 		rt:              rt,
+
+		unsupportedFields: unsupportedFields,
 	}, nil
 }
+
+// scanNestedField resolves the struct type of the given nested field, which is either a struct,
+// a pointer to a struct, a collection of structs, or an interface.
+// It returns false if the field holds none of them.
+func scanNestedField(c sensitiveStructContext, ssField *sensitiveField) (bool, error) {
+	tt := ssField.sf.Type
+	if tt.Kind() == reflect.Ptr {
+		tt = tt.Elem()
+	}
+	if tt.Kind() == reflect.Slice || tt.Kind() == reflect.Array {
+		ssField.isSlice = true
+		tt = tt.Elem()
+	}
+	if tt.Kind() == reflect.Map {
+		ssField.isMap = true
+		tt = tt.Elem()
+	}
+	if tt.Kind() == reflect.Ptr {
+		tt = tt.Elem()
+	}
+	if tt.Kind() == reflect.Interface {
+		// The struct type is resolved from the dynamic value at replace time.
+		ssField.isDynamic = true
+		return true, nil
+	}
+	if tt.Kind() != reflect.Struct {
+		return false, nil
+	}
+
+	_, seen := c.seen[tt]
+	if !seen {
+		var ssType sensitiveStructType
+		var err error
+		c.seen[tt] = &ssType
+		*c.scanned = append(*c.scanned, tt)
+		ssType, err = scanStructTypeWithContext(c, tt)
+		if err != nil {
+			return false, err
+		}
+		ssField.nestedStructType = &ssType
+	} else {
+		ssField.nestedStructTypeRef = tt
+	}
+	return true, nil
+}
+
+// validateKeysField checks that the 'keys' tag option is set on a map field with string keys.
+func validateKeysField(field reflect.StructField) error {
+	tt := field.Type
+	if tt.Kind() == reflect.Ptr {
+		tt = tt.Elem()
+	}
+	if tt.Kind() != reflect.Map || tt.Key().Kind() != reflect.String {
+		return fmt.Errorf("'keys' option requires a map with string keys, found '%v' in '%s'", field.Type, field.Name)
+	}
+	return nil
+}
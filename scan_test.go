@@ -2,6 +2,7 @@ package sensitive
 
 import (
 	"errors"
+	"net/netip"
 	"reflect"
 	"strconv"
 	"testing"
@@ -382,6 +383,38 @@ func TestScan(t *testing.T) {
 				err: ErrMultipleNestedSubjectID,
 			}
 		}(),
+		func() tc {
+			type T struct {
+				Profile  `sensitive:"dive"`
+				Address  [1]Address        `sensitive:"dive"`
+				Emails   []Email           `sensitive:"data"`
+				Contacts map[string]string `sensitive:"data"`
+			}
+			return tc{
+				val: &T{
+					Profile: Profile{
+						ID:    "abc",
+						Email: "email@example.com",
+					},
+					Address: [1]Address{
+						{
+							Street: "7234 Antone Springs",
+						},
+					},
+					Emails:   []Email{"email@example.com"},
+					Contacts: map[string]string{"A": "email@example.com"},
+				},
+				want: &T{
+					Profile: Profile{
+						ID: "abc",
+					},
+					Address:  [1]Address{{}},
+					Emails:   []Email{""},
+					Contacts: map[string]string{"A": ""},
+				},
+				ok: true,
+			}
+		}(),
 		func() tc {
 			type NestedAddress struct {
 				Address `sensitive:"dive"`
@@ -451,3 +484,396 @@ func TestScan(t *testing.T) {
 		})
 	}
 }
+
+func TestScan_FieldReplace(t *testing.T) {
+	type Shipping struct {
+		Address Address `sensitive:"dive"`
+	}
+	type Order struct {
+		Shipping *Shipping `sensitive:"dive"`
+	}
+	type T struct {
+		Profile  `sensitive:"dive"`
+		Orders   []Order            `sensitive:"dive"`
+		Contacts map[string]Address `sensitive:"dive,keys=email"`
+		Aliases  []string           `sensitive:"data"`
+	}
+
+	val := &T{
+		Profile: Profile{
+			ID:    "abc",
+			Email: "email@example.com",
+		},
+		Orders: []Order{
+			{},
+			{Shipping: &Shipping{Address: Address{Street: "7234 Antone Springs"}}},
+		},
+		Contacts: map[string]Address{
+			"email@example.com": {Street: "90 Kerluke Pine"},
+		},
+		Aliases: []string{"Kenna"},
+	}
+
+	s, err := Scan(val, true)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	got := map[string]FieldReplace{}
+	if err := s.Replace(func(fr FieldReplace, val string) (string, error) {
+		got[fr.Path] = fr
+		return val, nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	want := map[string]struct {
+		name   string
+		parent reflect.Type
+	}{
+		"Profile.Email":                      {"Email", reflect.TypeOf(Profile{})},
+		"Orders[1].Shipping.Address.Street":  {"Street", reflect.TypeOf(Address{})},
+		"Contacts[email@example.com].Street": {"Street", reflect.TypeOf(Address{})},
+		"Contacts[email@example.com]#key":    {"Contacts", reflect.TypeOf(T{})},
+		"Aliases[0]":                         {"Aliases", reflect.TypeOf(T{})},
+	}
+	if len(want) != len(got) {
+		t.Fatalf("want %d fields, got %d: %v", len(want), len(got), got)
+	}
+	for path, w := range want {
+		fr, ok := got[path]
+		if !ok {
+			t.Fatalf("expect to find field path %s, got %v", path, got)
+		}
+		if fr.Name != w.name || fr.Field.Name != w.name {
+			t.Fatalf("want field name %s, got %s", w.name, fr.Name)
+		}
+		if fr.Parent != w.parent {
+			t.Fatalf("want parent type %v, got %v", w.parent, fr.Parent)
+		}
+		if fr.SubjectID != "abc" {
+			t.Fatalf("want subject ID %s, got %s", "abc", fr.SubjectID)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	type T struct {
+		Profile  `sensitive:"dive"`
+		Contacts map[string]Address `sensitive:"dive"`
+		Aliases  []string           `sensitive:"data"`
+	}
+
+	val := T{
+		Profile: Profile{
+			ID:    "abc",
+			Email: "email@example.com",
+		},
+		Contacts: map[string]Address{
+			"A": {Street: "90 Kerluke Pine"},
+		},
+		Aliases: []string{"Kenna", "Kenna31"},
+	}
+
+	if _, err := Scan(val, false); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expect err is %v, got %v", ErrUnsupportedType, err)
+	}
+	if _, err := Inspect((*T)(nil), false); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expect err is %v, got %v", ErrUnsupportedType, err)
+	}
+
+	for _, v := range []any{val, &val} {
+		s, err := Inspect(v, true)
+		if err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+
+		got := map[string]string{}
+		if err := s.Walk(func(fr FieldReplace, val string) error {
+			got[fr.Path] = val
+			return nil
+		}); err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+
+		want := map[string]string{
+			"Profile.Email":      "email@example.com",
+			"Contacts[A].Street": "90 Kerluke Pine",
+			"Aliases[0]":         "Kenna",
+			"Aliases[1]":         "Kenna31",
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+
+	s, err := Inspect(val, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	// early termination
+	count := 0
+	if err := s.Walk(func(fr FieldReplace, val string) error {
+		count++
+		if count == 2 {
+			return SkipAll
+		}
+		return nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if count != 2 {
+		t.Fatalf("expect walk stops after %d fields, got %d", 2, count)
+	}
+
+	testErr := errors.New("test walk error")
+	if err := s.Walk(func(fr FieldReplace, val string) error {
+		return testErr
+	}); !errors.Is(err, testErr) {
+		t.Fatalf("expect err is %v, got %v", testErr, err)
+	}
+
+	// Replace has no effect on struct values
+	if err := s.Replace(func(fr FieldReplace, val string) (string, error) {
+		return "", nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if val.Email != "email@example.com" || val.Contacts["A"].Street != "90 Kerluke Pine" {
+		t.Fatalf("expect value be untouched, got %+v", val)
+	}
+}
+
+func TestWalkValues(t *testing.T) {
+	type T struct {
+		Email    string            `sensitive:"data"`
+		Age      *int              `sensitive:"data"`
+		Scores   []float32         `sensitive:"data"`
+		Avatar   []byte            `sensitive:"data"`
+		IPAddr   netip.Addr        `sensitive:"data"`
+		Phone    phoneNumber       `sensitive:"data"`
+		Balances map[string]uint16 `sensitive:"data,keys=name"`
+	}
+
+	val := T{
+		Email:    "email@example.com",
+		Age:      ptr(36),
+		Scores:   []float32{1.5},
+		Avatar:   []byte("avatar"),
+		IPAddr:   netip.MustParseAddr("169.251.207.194"),
+		Phone:    phoneNumber{CountryCode: "+1", Number: "2503080529"},
+		Balances: map[string]uint16{"Sarah": 100},
+	}
+
+	s, err := Inspect(val, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	got := map[string]any{}
+	if err := s.WalkValues(func(fr FieldReplace, val any) error {
+		got[fr.Path] = val
+		return nil
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	want := map[string]any{
+		"Email":               "email@example.com",
+		"Age":                 int64(36),
+		"Scores[0]":           float64(1.5),
+		"Avatar":              []byte("avatar"),
+		"IPAddr":              netip.MustParseAddr("169.251.207.194"),
+		"Phone":               phoneNumber{CountryCode: "+1", Number: "2503080529"},
+		"Balances[Sarah]":     uint64(100),
+		"Balances[Sarah]#key": "Sarah",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	// early termination
+	count := 0
+	if err := s.WalkValues(func(fr FieldReplace, val any) error {
+		count++
+		return SkipAll
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if count != 1 {
+		t.Fatalf("expect walk stops after %d fields, got %d", 1, count)
+	}
+}
+
+func TestCollectApply(t *testing.T) {
+	type T struct {
+		Profile  `sensitive:"dive"`
+		Contacts map[string]Address `sensitive:"dive,keys=email"`
+		Aliases  []string           `sensitive:"data"`
+	}
+
+	val := &T{
+		Profile: Profile{
+			ID:    "abc",
+			Email: "email@example.com",
+		},
+		Contacts: map[string]Address{
+			"contact@example.com": {Street: "90 Kerluke Pine"},
+		},
+		Aliases: []string{"Kenna", "Kenna31"},
+	}
+
+	s, err := Scan(val, true)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	fields, err := s.Collect()
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	got := map[string]string{}
+	for _, f := range fields {
+		if f.SubjectID != "abc" {
+			t.Fatalf("expect subject ID be %s, got %s", "abc", f.SubjectID)
+		}
+		got[f.Path] = f.Value
+	}
+	want := map[string]string{
+		"Profile.Email":                        "email@example.com",
+		"Contacts[contact@example.com].Street": "90 Kerluke Pine",
+		"Contacts[contact@example.com]#key":    "contact@example.com",
+		"Aliases[0]":                           "Kenna",
+		"Aliases[1]":                           "Kenna31",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	if err := s.Apply(map[string]string{
+		"Profile.Email":                        "ghost@example.com",
+		"Contacts[contact@example.com].Street": "Unknown",
+		"Contacts[contact@example.com]#key":    "ghost@example.com",
+		"Aliases[1]":                           "Ghost",
+		"Unknown.Path":                         "Ghost",
+	}); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	wantVal := &T{
+		Profile: Profile{
+			ID:    "abc",
+			Email: "ghost@example.com",
+		},
+		Contacts: map[string]Address{
+			"ghost@example.com": {Street: "Unknown"},
+		},
+		Aliases: []string{"Kenna", "Ghost"},
+	}
+	if !reflect.DeepEqual(wantVal, val) {
+		t.Fatalf("want %+v, got %+v", wantVal, val)
+	}
+}
+
+func TestReplace_ReferenceCycle(t *testing.T) {
+	type Node struct {
+		ID       string           `sensitive:"subjectID"`
+		Name     *string          `sensitive:"data"`
+		Next     *Node            `sensitive:"dive"`
+		Children map[string]*Node `sensitive:"dive"`
+	}
+
+	newGraph := func() *Node {
+		name := "Sarah Turcotte"
+		a := &Node{ID: "abc", Name: &name}
+		b := &Node{Name: &name, Next: a} // the name pointer is shared
+		a.Next = b
+		a.Children = map[string]*Node{"a": a, "b": b}
+		return a
+	}
+
+	t.Run("shared and cyclic references are replaced once", func(t *testing.T) {
+		val := newGraph()
+		s, err := Scan(val, true)
+		if err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+		if want, got := "abc", s.SubjectID(); want != got {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+
+		count := 0
+		if err := s.Replace(func(fr FieldReplace, val string) (string, error) {
+			count++
+			return "*", nil
+		}); err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+		if count != 1 {
+			t.Fatalf("expect replace func be called %d times, got %d", 1, count)
+		}
+		if *val.Name != "*" || *val.Next.Name != "*" {
+			t.Fatalf("expect values be replaced, got %s, %s", *val.Name, *val.Next.Name)
+		}
+	})
+
+	t.Run("reference cycles fail if configured", func(t *testing.T) {
+		s, err := Scan(newGraph(), false, func(sc *ScanConfig) {
+			sc.ErrorOnReferenceCycle = true
+		})
+		if err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+		if err := s.Walk(func(fr FieldReplace, val string) error {
+			return nil
+		}); !errors.Is(err, ErrReferenceCycle) {
+			t.Fatalf("expect err is %v, got %v", ErrReferenceCycle, err)
+		}
+
+		err = Redact(newGraph(), func(rc *RedactConfig) {
+			rc.ErrorOnReferenceCycle = true
+		})
+		if !errors.Is(err, ErrReferenceCycle) {
+			t.Fatalf("expect err is %v, got %v", ErrReferenceCycle, err)
+		}
+	})
+
+	t.Run("shared references do not fail if configured", func(t *testing.T) {
+		name := "Sarah Turcotte"
+		leaf := &Node{Name: &name}
+		val := &Node{
+			Next:     leaf,
+			Children: map[string]*Node{"leaf": leaf},
+		}
+		if err := Redact(val, func(rc *RedactConfig) {
+			rc.ErrorOnReferenceCycle = true
+		}); err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+		if want := "**************"; *leaf.Name != want {
+			t.Fatalf("want %s, got %s", want, *leaf.Name)
+		}
+	})
+
+	t.Run("cyclic values are copied", func(t *testing.T) {
+		val := newGraph()
+		got, err := RedactCopy(val)
+		if err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+		if got.Next.Next != got || got.Children["a"] != got || got.Children["b"] != got.Next {
+			t.Fatal("expect references be preserved in the copy")
+		}
+		if got.Name != got.Next.Name {
+			t.Fatal("expect shared data pointers be preserved in the copy")
+		}
+		if want := "**************"; *got.Name != want {
+			t.Fatalf("want %s, got %s", want, *got.Name)
+		}
+		if want := "Sarah Turcotte"; *val.Name != want {
+			t.Fatalf("expect original value be untouched, got %s", *val.Name)
+		}
+	})
+}
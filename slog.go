@@ -0,0 +1,244 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// LogValue returns a masked representation of the given value suitable for structured logging.
+//
+// If the value is a struct (or a struct pointer) containing sensitive data, it is masked
+// using the registered masks (see [Mask]) on a copy, hence the original value is left untouched.
+// Slices, arrays and maps holding such structs are copied, and their sensitive structs are masked.
+// Other values are returned as is.
+//
+// It never returns the cleartext value of a sensitive struct;
+// if masking fails, the returned value reports the error instead.
+func LogValue(v any, opts ...func(*RedactConfig)) slog.Value {
+	if v == nil {
+		return slog.AnyValue(v)
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return slog.AnyValue(v)
+	}
+
+	masked, ok, err := maskLogValue(reflect.ValueOf(v), append(opts, WithRegisteredMasks), map[visitKey]struct{}{})
+	if err != nil {
+		return slog.StringValue("!ERROR:" + err.Error())
+	}
+	if !ok {
+		return slog.AnyValue(v)
+	}
+	return slog.AnyValue(masked.Interface())
+}
+
+// maskLogValue returns a masked copy of the given value if it is a sensitive struct, a struct pointer,
+// or a collection holding some of them. It reports whether the value has been masked.
+//
+// The path parameter tracks the references of the current traversal path; a reference cycle is reported as an error
+// since the sensitive structs it holds would be logged in cleartext otherwise.
+func maskLogValue(rv reflect.Value, opts []func(*RedactConfig), path map[visitKey]struct{}) (reflect.Value, bool, error) {
+	if key, ok := refKey(rv); ok {
+		if _, ok := path[key]; ok {
+			return rv, false, fmt.Errorf("%w at '%v'", ErrReferenceCycle, rv.Type())
+		}
+		path[key] = struct{}{}
+		defer delete(path, key)
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv, false, nil
+		}
+		mv, ok, err := maskLogValue(rv.Elem(), opts, path)
+		if !ok || err != nil {
+			return rv, ok, err
+		}
+		cv := reflect.New(rv.Type()).Elem()
+		cv.Set(mv)
+		return cv, true, nil
+
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return rv, false, nil
+		}
+		if rv.Elem().Kind() == reflect.Struct {
+			return maskLogStruct(rv, opts)
+		}
+		mv, ok, err := maskLogValue(rv.Elem(), opts, path)
+		if !ok || err != nil {
+			return rv, ok, err
+		}
+		cv := reflect.New(rv.Type().Elem())
+		cv.Elem().Set(mv)
+		return cv, true, nil
+
+	case reflect.Struct:
+		return maskLogStruct(rv, opts)
+
+	case reflect.Slice, reflect.Array:
+		if !mayHoldStruct(rv.Type().Elem()) {
+			return rv, false, nil
+		}
+		var cv reflect.Value
+		for i := 0; i < rv.Len(); i++ {
+			mv, ok, err := maskLogValue(rv.Index(i), opts, path)
+			if err != nil {
+				return rv, true, err
+			}
+			if !ok {
+				continue
+			}
+			if !cv.IsValid() {
+				// the collection is copied once a sensitive element is found.
+				if rv.Kind() == reflect.Slice {
+					cv = reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+				} else {
+					cv = reflect.New(rv.Type()).Elem()
+				}
+				reflect.Copy(cv, rv)
+			}
+			cv.Index(i).Set(mv)
+		}
+		return cv, cv.IsValid(), nil
+
+	case reflect.Map:
+		if !mayHoldStruct(rv.Type().Elem()) {
+			return rv, false, nil
+		}
+		var cv reflect.Value
+		iter := rv.MapRange()
+		for iter.Next() {
+			mv, ok, err := maskLogValue(iter.Value(), opts, path)
+			if err != nil {
+				return rv, true, err
+			}
+			if !ok {
+				continue
+			}
+			if !cv.IsValid() {
+				cv = reflect.MakeMapWithSize(rv.Type(), rv.Len())
+				cvIter := rv.MapRange()
+				for cvIter.Next() {
+					cv.SetMapIndex(cvIter.Key(), cvIter.Value())
+				}
+			}
+			cv.SetMapIndex(iter.Key(), mv)
+		}
+		return cv, cv.IsValid(), nil
+	}
+
+	return rv, false, nil
+}
+
+// mayHoldStruct reports whether values of the given collection element type might hold structs,
+// so that large collections of numbers or bytes are not visited element by element.
+func mayHoldStruct(rt reflect.Type) bool {
+	if isBytesType(rt) {
+		return false
+	}
+	switch rt.Kind() {
+	case reflect.Struct, reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// maskLogStruct returns a masked copy of the given struct or struct pointer if it contains sensitive data.
+func maskLogStruct(rv reflect.Value, opts []func(*RedactConfig)) (reflect.Value, bool, error) {
+	if !rv.CanInterface() {
+		return rv, false, nil
+	}
+	v := rv.Interface()
+	found, err := Check(v, func(sc *ScanConfig) {
+		sc.Strict = false
+	})
+	if errors.Is(err, ErrUnsupportedType) || (err == nil && !found) {
+		return rv, false, nil
+	}
+	if err != nil {
+		return rv, true, err
+	}
+
+	masked, err := redactCopy(v, opts)
+	if err != nil {
+		return rv, true, err
+	}
+	return reflect.ValueOf(masked), true, nil
+}
+
+// NewSlogHandler returns a [slog.Handler] that masks sensitive struct attributes
+// using [LogValue] before passing the record to the inner handler.
+//
+// Attributes nested in groups and attributes added via [slog.Logger.With] are masked as well.
+func NewSlogHandler(inner slog.Handler, opts ...func(*RedactConfig)) slog.Handler {
+	return &slogHandler{
+		inner: inner,
+		opts:  opts,
+	}
+}
+
+type slogHandler struct {
+	inner slog.Handler
+	opts  []func(*RedactConfig)
+}
+
+var _ slog.Handler = &slogHandler{}
+
+// Enabled implements slog.Handler.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.maskAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, nr)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		masked = append(masked, h.maskAttr(a))
+	}
+	return &slogHandler{
+		inner: h.inner.WithAttrs(masked),
+		opts:  h.opts,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{
+		inner: h.inner.WithGroup(name),
+		opts:  h.opts,
+	}
+}
+
+func (h *slogHandler) maskAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		masked := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			masked = append(masked, h.maskAttr(ga))
+		}
+		a.Value = slog.GroupValue(masked...)
+
+	case slog.KindAny:
+		a.Value = LogValue(a.Value.Any(), h.opts...)
+	}
+
+	return a
+}
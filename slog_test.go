@@ -0,0 +1,204 @@
+package sensitive
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	type tc struct {
+		val  any
+		want any
+	}
+
+	profile := func() Profile {
+		return Profile{
+			Email:    "email@example.com",
+			Fullname: "Guadalupe Kemmer DDS",
+		}
+	}
+	masked := Profile{
+		Email:    "*****@example.com",
+		Fullname: "********************",
+	}
+
+	tcs := []tc{
+		{
+			val:  nil,
+			want: nil,
+		},
+		{
+			val:  "email@example.com",
+			want: "email@example.com",
+		},
+		{
+			val:  (*Profile)(nil),
+			want: (*Profile)(nil),
+		},
+		{
+			val:  struct{ Email string }{Email: "email@example.com"},
+			want: struct{ Email string }{Email: "email@example.com"},
+		},
+		{
+			val:  profile(),
+			want: masked,
+		},
+		{
+			val:  ptr(profile()),
+			want: &masked,
+		},
+		{
+			val:  &InvalidTag{Data: "abc"},
+			want: "!ERROR:" + ErrInvalidTagConfiguration.Error(),
+		},
+		{
+			val:  []Profile{profile(), {}},
+			want: []Profile{masked, {}},
+		},
+		{
+			val:  []*Profile{ptr(profile()), nil},
+			want: []*Profile{&masked, nil},
+		},
+		{
+			val:  [1][]Profile{{profile()}},
+			want: [1][]Profile{{masked}},
+		},
+		{
+			val:  map[string]any{"a": profile(), "b": "email@example.com"},
+			want: map[string]any{"a": masked, "b": "email@example.com"},
+		},
+		{
+			val:  []string{"email@example.com"},
+			want: []string{"email@example.com"},
+		},
+		{
+			val:  map[string][]byte{"a": []byte("email@example.com")},
+			want: map[string][]byte{"a": []byte("email@example.com")},
+		},
+		{
+			val:  []any{&InvalidTag{Data: "abc"}},
+			want: "!ERROR:" + ErrInvalidTagConfiguration.Error(),
+		},
+		{
+			val: func() any {
+				l := []any{profile(), nil}
+				l[1] = l
+				return l
+			}(),
+			want: "!ERROR:" + ErrReferenceCycle.Error(),
+		},
+		{
+			val: &Profile{
+				Email: "invalid_email.com",
+			},
+			want: &Profile{
+				Email: "*****************",
+			},
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			got := LogValue(tc.val).Any()
+			if want, ok := tc.want.(string); ok && strings.HasPrefix(want, "!ERROR:") {
+				if s, _ := got.(string); !strings.HasPrefix(s, want) {
+					t.Fatalf("want %v, got %v", want, got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+
+	// the original value must be left untouched
+	p := profile()
+	_ = LogValue(&p)
+	if !reflect.DeepEqual(profile(), p) {
+		t.Fatalf("expect original value be untouched, got %+v", p)
+	}
+	l := []*Profile{ptr(profile())}
+	_ = LogValue(l)
+	if !reflect.DeepEqual(profile(), *l[0]) {
+		t.Fatalf("expect original value be untouched, got %+v", *l[0])
+	}
+}
+
+func TestMayHoldStruct(t *testing.T) {
+	type tc struct {
+		val  any
+		want bool
+	}
+
+	tcs := []tc{
+		{val: 0, want: false},
+		{val: "", want: false},
+		{val: []byte(nil), want: false},
+		{val: Profile{}, want: true},
+		{val: (*int)(nil), want: true},
+		{val: []any(nil), want: true},
+		{val: [1]int{}, want: true},
+		{val: map[string]int(nil), want: true},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			if got := mayHoldStruct(reflect.TypeOf(tc.val)); got != tc.want {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func BenchmarkLogValue_Bytes(b *testing.B) {
+	val := make([]byte, 5<<20)
+	for i := 0; i < b.N; i++ {
+		_ = LogValue(val)
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	p := Profile{
+		Email:    "email@example.com",
+		Fullname: "Guadalupe Kemmer DDS",
+	}
+
+	logger.
+		With("profile", p).
+		WithGroup("request").
+		Info("msg",
+			"user", &p,
+			slog.Group("nested", "profile", p),
+			"list", []Profile{p},
+			"role", "Teacher",
+		)
+
+	got := buf.String()
+	if strings.Contains(got, "email@example.com") || strings.Contains(got, "Guadalupe") {
+		t.Fatalf("expect sensitive data be masked, got %s", got)
+	}
+	if want := "*****@example.com"; strings.Count(got, want) != 4 {
+		t.Fatalf("expect %s be found 4 times, got %s", want, got)
+	}
+	if want := "request.role=Teacher"; !strings.Contains(got, want) {
+		t.Fatalf("expect %s be found, got %s", want, got)
+	}
+	if p.Email != "email@example.com" {
+		t.Fatalf("expect original value be untouched, got %+v", p)
+	}
+}
@@ -0,0 +1,81 @@
+package sensitive
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrInvalidTextValue = errors.New("invalid sensitive text replacement value")
+)
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isTextType reports whether the given type is processed as text, i.e. it is neither a string, a scalar
+// nor a bytes type, and it implements both [encoding.TextMarshaler] and [encoding.TextUnmarshaler].
+func isTextType(rt reflect.Type) bool {
+	if rt.Kind() == reflect.String || isScalarType(rt) || isBytesType(rt) {
+		return false
+	}
+	pt := reflect.PointerTo(rt)
+	return pt.Implements(textMarshalerType) && pt.Implements(textUnmarshalerType)
+}
+
+// IsText reports whether the sensitive value is a text value, i.e. a type that implements
+// [encoding.TextMarshaler] and [encoding.TextUnmarshaler] such as [net/netip.Addr].
+//
+// Text values are replaced by round-tripping through their text form; the replacement
+// must be accepted by the UnmarshalText method, except for an empty string, which sets the zero value.
+func (fr FieldReplace) IsText() bool {
+	rt := fr.RType
+	if rt == nil || fr.MapKey {
+		return false
+	}
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return isTextType(rt)
+}
+
+// textValue returns the text form of the given text value.
+func textValue(v reflect.Value) (string, error) {
+	if !v.CanAddr() {
+		// the MarshalText method might have a pointer receiver.
+		cv := reflect.New(v.Type()).Elem()
+		cv.Set(v)
+		v = cv
+	}
+	text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// setTextValue parses the given text and sets the result to the text value.
+// An empty text sets the zero value.
+func setTextValue(v reflect.Value, text string) error {
+	if text == "" {
+		v.SetZero()
+		return nil
+	}
+	nv := reflect.New(v.Type())
+	if err := nv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTextValue, err)
+	}
+	v.Set(nv.Elem())
+	return nil
+}
+
+// validText reports whether the given text is accepted by the UnmarshalText method of the given type.
+func validText(rt reflect.Type, text string) bool {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return setTextValue(reflect.New(rt).Elem(), text) == nil
+}
@@ -0,0 +1,197 @@
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRedact_Text(t *testing.T) {
+	type T struct {
+		IP     netip.Addr            `sensitive:"data,kind=ipv4_addr"`
+		IPPtr  *netip.Addr           `sensitive:"data"`
+		IPs    []netip.Addr          `sensitive:"data"`
+		Routes map[string]netip.Addr `sensitive:"data"`
+	}
+
+	ip := netip.MustParseAddr("169.251.207.194")
+
+	type tc struct {
+		val    any
+		want   any
+		option func(*RedactConfig)
+		ok     bool
+		err    error
+	}
+
+	tcs := []tc{
+		{
+			val: &T{
+				IP:     ip,
+				IPPtr:  ptr(ip),
+				IPs:    []netip.Addr{ip},
+				Routes: map[string]netip.Addr{"home": ip},
+			},
+			want: &T{
+				IP:     netip.Addr{},
+				IPPtr:  ptr(netip.Addr{}),
+				IPs:    []netip.Addr{{}},
+				Routes: map[string]netip.Addr{"home": {}},
+			},
+			ok: true,
+		},
+		{
+			val: &T{
+				IP: ip,
+			},
+			want: &T{
+				IP: netip.MustParseAddr("169.251.207.0"),
+			},
+			option: func(rc *RedactConfig) {
+				rc.RedactFunc = func(fr FieldReplace, val string) (string, error) {
+					if !fr.IsText() {
+						t.Fatalf("expect field %s be text", fr.Path)
+					}
+					return netip.MustParsePrefix(val + "/24").Masked().Addr().String(), nil
+				}
+			},
+			ok: true,
+		},
+		{
+			val: &T{
+				IP: ip,
+			},
+			option: func(rc *RedactConfig) {
+				rc.RedactFunc = func(fr FieldReplace, val string) (string, error) {
+					return "***", nil
+				}
+			},
+			ok:  false,
+			err: ErrInvalidTextValue,
+		},
+		{
+			// masks whose result is not accepted by the text value fail, hence the value is fully redacted.
+			val: &T{
+				IP: ip,
+			},
+			want: &T{
+				IP: netip.Addr{},
+			},
+			option: WithRegisteredMasks,
+			ok:     true,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			err := Redact(tc.val, tc.option)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(tc.want, tc.val) {
+				t.Fatalf("want %+v, got %+v", tc.want, tc.val)
+			}
+		})
+	}
+}
+
+func TestWalk_Text(t *testing.T) {
+	type T struct {
+		IP netip.Addr `sensitive:"data"`
+	}
+
+	s, err := Inspect(T{IP: netip.MustParseAddr("169.251.207.194")}, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	values, err := s.Collect()
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if len(values) != 1 || values[0].Value != "169.251.207.194" {
+		t.Fatalf("unexpected collected values %+v", values)
+	}
+}
+
+func TestRedact_Text_FailClosed(t *testing.T) {
+	type T struct {
+		IPAddr netip.Addr `sensitive:"data,kind=ipv4_addr"`
+		Email  string     `sensitive:"data,kind=email"`
+	}
+
+	newT := func() *T {
+		return &T{
+			IPAddr: netip.MustParseAddr("169.251.207.194"),
+			Email:  "invalid_email.com",
+		}
+	}
+
+	type tc struct {
+		redact func(val *T) error
+		want   *T
+	}
+
+	tcs := []tc{
+		{
+			redact: func(val *T) error {
+				return Redact(val, func(rc *RedactConfig) {
+					rc.ContinueOnError = true
+					rc.RedactFunc = func(fr FieldReplace, val string) (string, error) {
+						return "****", nil
+					}
+				})
+			},
+			want: &T{Email: "****"},
+		},
+		{
+			redact: func(val *T) error {
+				return Mask(val, func(rc *RedactConfig) {
+					rc.OnMaskError = OnMaskErrorConstant
+					rc.MaskErrorConstant = "[redacted]"
+				})
+			},
+			want: &T{Email: "[redacted]"},
+		},
+		{
+			redact: func(val *T) error {
+				return RedactMany([]*T{val}, func(rc *RedactConfig) {
+					rc.BulkRedactFunc = func(ctx context.Context, fields []FieldValue) ([]string, error) {
+						values := make([]string, len(fields))
+						for i := range fields {
+							values[i] = "tok_" + strconv.Itoa(i)
+						}
+						return values, nil
+					}
+				})
+			},
+			want: &T{Email: "tok_1"},
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			val := newT()
+			err := tc.redact(val)
+			var fieldErr *FieldError
+			if !errors.Is(err, ErrInvalidTextValue) || !errors.As(err, &fieldErr) {
+				t.Fatalf("expect err be a field error of %v, got %v", ErrInvalidTextValue, err)
+			}
+			if fieldErr.Path != "IPAddr" || fieldErr.Kind != "ipv4_addr" {
+				t.Fatalf("unexpected field error %+v", fieldErr)
+			}
+			// the rejected text value is zeroed, and the remaining fields are redacted.
+			if !reflect.DeepEqual(tc.want, val) {
+				t.Fatalf("want %+v, got %+v", tc.want, val)
+			}
+		})
+	}
+}
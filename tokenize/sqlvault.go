@@ -0,0 +1,101 @@
+package tokenize
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/ln80/struct-sensitive/internal/option"
+)
+
+var (
+	ErrInvalidTableName = errors.New("invalid vault table name")
+)
+
+// SQLVaultConfig presents the configuration of [SQLVault].
+type SQLVaultConfig struct {
+	// Table is the name of the table that holds the tokens.
+	// It defaults to `sensitive_tokens`.
+	Table string
+
+	// Placeholder returns the query placeholder of the n-th parameter, starting from 1.
+	// It defaults to `?`; use [DollarPlaceholder] for PostgreSQL-like drivers.
+	Placeholder func(n int) string
+}
+
+// DollarPlaceholder returns PostgreSQL-like query placeholders, i.e. `$1`, `$2`...
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+var tableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLVault is an implementation of [Vault] backed by a SQL database through [database/sql].
+//
+// Use [SQLVault.CreateTable] to create the tokens table, or create it beforehand with the following columns:
+// `subject_id`, `token` and `value` of a text type, and a primary key on (`subject_id`, `token`).
+type SQLVault struct {
+	db *sql.DB
+
+	createQuery, storeQuery, loadQuery, purgeQuery string
+}
+
+var _ Vault = &SQLVault{}
+
+// NewSQLVault returns a vault backed by the given database.
+// It returns [ErrInvalidTableName] if the configured table name is not a valid SQL identifier.
+func NewSQLVault(db *sql.DB, opts ...func(*SQLVaultConfig)) (*SQLVault, error) {
+	cfg := SQLVaultConfig{
+		Table:       "sensitive_tokens",
+		Placeholder: func(int) string { return "?" },
+	}
+	option.Apply(&cfg, opts)
+
+	if !tableNameRegex.MatchString(cfg.Table) {
+		return nil, fmt.Errorf("%w '%s'", ErrInvalidTableName, cfg.Table)
+	}
+	p := cfg.Placeholder
+
+	return &SQLVault{
+		db: db,
+		createQuery: "CREATE TABLE IF NOT EXISTS " + cfg.Table +
+			" (subject_id VARCHAR(255) NOT NULL, token VARCHAR(255) NOT NULL, value TEXT NOT NULL, PRIMARY KEY (subject_id, token))",
+		storeQuery: "INSERT INTO " + cfg.Table + " (subject_id, token, value) VALUES (" + p(1) + ", " + p(2) + ", " + p(3) + ")",
+		loadQuery:  "SELECT value FROM " + cfg.Table + " WHERE subject_id = " + p(1) + " AND token = " + p(2),
+		purgeQuery: "DELETE FROM " + cfg.Table + " WHERE subject_id = " + p(1),
+	}, nil
+}
+
+// CreateTable creates the tokens table if it does not exist.
+func (v *SQLVault) CreateTable(ctx context.Context) error {
+	_, err := v.db.ExecContext(ctx, v.createQuery)
+	return err
+}
+
+// Store implements Vault.
+func (v *SQLVault) Store(ctx context.Context, subjectID, token, value string) error {
+	_, err := v.db.ExecContext(ctx, v.storeQuery, subjectID, token, value)
+	return err
+}
+
+// Load implements Vault.
+func (v *SQLVault) Load(ctx context.Context, subjectID, token string) (string, error) {
+	var value string
+	err := v.db.QueryRowContext(ctx, v.loadQuery, subjectID, token).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrTokenNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// Purge implements Vault.
+func (v *SQLVault) Purge(ctx context.Context, subjectID string) error {
+	_, err := v.db.ExecContext(ctx, v.purgeQuery, subjectID)
+	return err
+}
@@ -0,0 +1,163 @@
+package tokenize
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is a minimal in-memory SQL driver that understands the queries issued by SQLVault.
+type fakeDriver struct {
+	mu     sync.Mutex
+	tables map[string]map[[2]string]string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error { return nil }
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) table() string {
+	fields := strings.Fields(s.query)
+	for i, f := range fields {
+		if f == "EXISTS" || f == "INTO" || f == "FROM" {
+			return fields[i+1]
+		}
+	}
+	return ""
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	table := s.table()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+		if _, ok := s.d.tables[table]; !ok {
+			s.d.tables[table] = make(map[[2]string]string)
+		}
+	case strings.HasPrefix(s.query, "INSERT"):
+		rows, ok := s.d.tables[table]
+		if !ok {
+			return nil, errors.New("table not found")
+		}
+		rows[[2]string{args[0].(string), args[1].(string)}] = args[2].(string)
+	case strings.HasPrefix(s.query, "DELETE"):
+		for k := range s.d.tables[table] {
+			if k[0] == args[0].(string) {
+				delete(s.d.tables[table], k)
+			}
+		}
+	default:
+		return nil, errors.New("unsupported query: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	if !strings.HasPrefix(s.query, "SELECT value") {
+		return nil, errors.New("unsupported query: " + s.query)
+	}
+	rows := &fakeRows{}
+	if value, ok := s.d.tables[s.table()][[2]string{args[0].(string), args[1].(string)}]; ok {
+		rows.values = append(rows.values, value)
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	values []string
+}
+
+func (r *fakeRows) Columns() []string { return []string{"value"} }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+var registerFakeDriver sync.Once
+
+func openFakeDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	registerFakeDriver.Do(func() {
+		sql.Register("tokenize_fake", &fakeDriver{tables: make(map[string]map[[2]string]string)})
+	})
+	db, err := sql.Open("tokenize_fake", "")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLVault(t *testing.T) {
+	db := openFakeDB(t)
+
+	for _, table := range []string{"", "tokens; DROP TABLE users", "1tokens"} {
+		if _, err := NewSQLVault(db, func(c *SQLVaultConfig) { c.Table = table }); !errors.Is(err, ErrInvalidTableName) {
+			t.Fatalf("expect err is %v, got %v", ErrInvalidTableName, err)
+		}
+	}
+
+	v, err := NewSQLVault(db)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := v.CreateTable(context.Background()); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	testVault(t, v)
+}
+
+func TestSQLVault_Config(t *testing.T) {
+	v, err := NewSQLVault(openFakeDB(t), func(c *SQLVaultConfig) {
+		c.Table = "vault.tokens"
+		c.Placeholder = DollarPlaceholder
+	})
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	if want := "SELECT value FROM vault.tokens WHERE subject_id = $1 AND token = $2"; v.loadQuery != want {
+		t.Fatalf("want %s, got %s", want, v.loadQuery)
+	}
+	if want := "INSERT INTO vault.tokens (subject_id, token, value) VALUES ($1, $2, $3)"; v.storeQuery != want {
+		t.Fatalf("want %s, got %s", want, v.storeQuery)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Package tokenize implements reversible tokenization of sensitive struct fields.
+
+Each sensitive data field is replaced with a random opaque token, while the original value
+is stored in a [Vault]. Tokens are scoped by the subject to whom the data belongs
+(see the `subjectID` tag), so that all the tokens of a subject can be purged together.
+
+	type Profile struct {
+		ID    string `sensitive:"subjectID"`
+		Email string `sensitive:"data,kind=email"`
+	}
+
+	vault := tokenize.NewMemoryVault()
+
+	p := Profile{ID: "abc", Email: "eric.prosacco@example.com"}
+
+	_ = tokenize.Tokenize(&p, vault)   // p.Email is now a token, e.g. "tok_2vG3..."
+	_ = tokenize.Detokenize(&p, vault) // p.Email is back in cleartext
+
+	_ = tokenize.Purge(vault, "abc") // p.Email tokens can't be detokenized anymore
+*/
+package tokenize
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	sensitive "github.com/ln80/struct-sensitive"
+)
+
+var (
+	ErrTokenNotFound = errors.New("token not found")
+	ErrInvalidToken  = errors.New("invalid token")
+)
+
+// TokenPrefix is the prefix of the generated tokens.
+const TokenPrefix = "tok_"
+
+// tokenSize is the size in bytes of the random part of the generated tokens.
+const tokenSize = 16
+
+// Tokenize replaces the sensitive data fields of the given struct pointer with random tokens,
+// and stores the original values in the vault.
+//
+// It returns an error if the struct does not have a subject ID.
+// Only string data fields can be tokenized; it returns [sensitive.ErrUnsupportedFieldType]
+// before tokenizing anything if the struct has other data fields, e.g. numbers, bytes or text types such as [net/netip.Addr].
+func Tokenize(structPtr any, v Vault) error {
+	return TokenizeContext(context.Background(), structPtr, v)
+}
+
+// TokenizeContext is like [Tokenize] but passes the given context to the vault.
+func TokenizeContext(ctx context.Context, structPtr any, v Vault) error {
+	accessor, err := sensitive.Scan(structPtr, true)
+	if err != nil {
+		return err
+	}
+	if !accessor.HasSensitive() {
+		return nil
+	}
+	if err := checkFields(accessor); err != nil {
+		return err
+	}
+
+	return accessor.ReplaceContext(ctx, func(ctx context.Context, fr sensitive.FieldReplace, val string) (string, error) {
+		token, err := newToken()
+		if err != nil {
+			return "", err
+		}
+		if err := v.Store(ctx, fr.SubjectID, token, val); err != nil {
+			return "", err
+		}
+		return token, nil
+	})
+}
+
+// Detokenize replaces the tokens of the given struct pointer with the original values stored in the vault.
+//
+// It returns [ErrTokenNotFound] if a token is not found in the vault, e.g. if the subject's tokens
+// have been purged, and [ErrInvalidToken] if a sensitive field value is not a token.
+// Like [Tokenize], it returns [sensitive.ErrUnsupportedFieldType] if the struct has data fields that can't be tokenized.
+func Detokenize(structPtr any, v Vault) error {
+	return DetokenizeContext(context.Background(), structPtr, v)
+}
+
+// DetokenizeContext is like [Detokenize] but passes the given context to the vault.
+func DetokenizeContext(ctx context.Context, structPtr any, v Vault) error {
+	accessor, err := sensitive.Scan(structPtr, true)
+	if err != nil {
+		return err
+	}
+	if !accessor.HasSensitive() {
+		return nil
+	}
+	if err := checkFields(accessor); err != nil {
+		return err
+	}
+
+	return accessor.ReplaceContext(ctx, func(ctx context.Context, fr sensitive.FieldReplace, token string) (string, error) {
+		if !strings.HasPrefix(token, TokenPrefix) {
+			return "", ErrInvalidToken
+		}
+		val, err := v.Load(ctx, fr.SubjectID, token)
+		if err != nil {
+			return "", err
+		}
+		return val, nil
+	})
+}
+
+// Purge deletes all the tokens of the given subject from the vault.
+// As a result, the subject's tokenized data can't be detokenized anymore.
+func Purge(v Vault, subjectID string) error {
+	return PurgeContext(context.Background(), v, subjectID)
+}
+
+// PurgeContext is like [Purge] but passes the given context to the vault.
+func PurgeContext(ctx context.Context, v Vault, subjectID string) error {
+	return v.Purge(ctx, subjectID)
+}
+
+// checkFields returns an error if the sensitive data fields of the struct are not all strings,
+// so that the struct is never left partially tokenized.
+func checkFields(accessor sensitive.Struct) error {
+	return accessor.WalkValues(func(fr sensitive.FieldReplace, val any) error {
+		if _, ok := val.(string); ok {
+			return nil
+		}
+		return fmt.Errorf("%w: field '%s' of type '%v' can't be tokenized", sensitive.ErrUnsupportedFieldType, fr.Path, fr.RType)
+	})
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return TokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
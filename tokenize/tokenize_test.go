@@ -0,0 +1,194 @@
+package tokenize_test
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	sensitive "github.com/ln80/struct-sensitive"
+	"github.com/ln80/struct-sensitive/tokenize"
+)
+
+type Device struct {
+	IPAddr string `sensitive:"data,kind=ipv4_addr"`
+}
+
+type Profile struct {
+	ID      string            `sensitive:"subjectID"`
+	Email   string            `sensitive:"data,kind=email"`
+	Phone   *string           `sensitive:"data"`
+	Aliases []string          `sensitive:"data"`
+	Devices map[string]Device `sensitive:"dive"`
+	Role    string
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
+
+func TestTokenize(t *testing.T) {
+	type tc struct {
+		val func() any
+		ok  bool
+		err error
+	}
+
+	tcs := []tc{
+		{
+			val: func() any { return Profile{ID: "abc"} },
+			ok:  false,
+			err: sensitive.ErrUnsupportedType,
+		},
+		{
+			val: func() any { return &Profile{Email: "email@example.com"} },
+			ok:  false,
+			err: sensitive.ErrSubjectIDNotFound,
+		},
+		{
+			val: func() any {
+				return &struct{ Email string }{Email: "email@example.com"}
+			},
+			ok: true,
+		},
+		{
+			val: func() any {
+				return &Profile{
+					ID:      "abc",
+					Email:   "email@example.com",
+					Phone:   ptr("250-308-0529"),
+					Aliases: []string{"Kenna", "Kenna31"},
+					Devices: map[string]Device{
+						"home": {IPAddr: "169.251.207.194"},
+					},
+					Role: "Teacher",
+				}
+			},
+			ok: true,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			vault := tokenize.NewMemoryVault()
+
+			val, original := tc.val(), tc.val()
+			err := tokenize.Tokenize(val, vault)
+			if !tc.ok {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expect err is %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+
+			if p, ok := val.(*Profile); ok {
+				for _, token := range append([]string{p.Email, *p.Phone, p.Devices["home"].IPAddr}, p.Aliases...) {
+					if !strings.HasPrefix(token, tokenize.TokenPrefix) {
+						t.Fatalf("expect value be tokenized, got %s", token)
+					}
+				}
+				if p.Role != "Teacher" || p.ID != "abc" {
+					t.Fatalf("expect non-sensitive fields be untouched, got %+v", p)
+				}
+			}
+
+			if err := tokenize.Detokenize(val, vault); err != nil {
+				t.Fatal("expect err be nil, got", err)
+			}
+			if !reflect.DeepEqual(original, val) {
+				t.Fatalf("want %+v, got %+v", original, val)
+			}
+		})
+	}
+}
+
+func TestDetokenize(t *testing.T) {
+	vault := tokenize.NewMemoryVault()
+
+	p := &Profile{ID: "abc", Email: "email@example.com"}
+	if err := tokenize.Tokenize(p, vault); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	// tokens are scoped by subject
+	other := &Profile{ID: "def", Email: p.Email}
+	if err := tokenize.Detokenize(other, vault); !errors.Is(err, tokenize.ErrTokenNotFound) {
+		t.Fatalf("expect err is %v, got %v", tokenize.ErrTokenNotFound, err)
+	}
+
+	invalid := &Profile{ID: "abc", Email: "email@example.com"}
+	if err := tokenize.Detokenize(invalid, vault); !errors.Is(err, tokenize.ErrInvalidToken) {
+		t.Fatalf("expect err is %v, got %v", tokenize.ErrInvalidToken, err)
+	}
+
+	if err := tokenize.Purge(vault, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := tokenize.Detokenize(p, vault); !errors.Is(err, tokenize.ErrTokenNotFound) {
+		t.Fatalf("expect err is %v, got %v", tokenize.ErrTokenNotFound, err)
+	}
+}
+
+func TestTokenize_UnsupportedField(t *testing.T) {
+	type tc struct {
+		val  func() any
+		want any
+	}
+
+	type Patient struct {
+		ID    string `sensitive:"subjectID"`
+		Email string `sensitive:"data"`
+		Age   int    `sensitive:"data"`
+		Notes string `sensitive:"data"`
+	}
+	type Document struct {
+		ID    string `sensitive:"subjectID"`
+		Email string `sensitive:"data"`
+		Scan  []byte `sensitive:"data"`
+	}
+	type Session struct {
+		ID     string     `sensitive:"subjectID"`
+		Email  string     `sensitive:"data"`
+		IPAddr netip.Addr `sensitive:"data"`
+		Agent  string     `sensitive:"data"`
+	}
+
+	tcs := []tc{
+		{
+			val:  func() any { return &Patient{ID: "abc", Email: "email@example.com", Age: 36, Notes: "notes"} },
+			want: &Patient{ID: "abc", Email: "email@example.com", Age: 36, Notes: "notes"},
+		},
+		{
+			val:  func() any { return &Document{ID: "abc", Email: "email@example.com", Scan: []byte("scan")} },
+			want: &Document{ID: "abc", Email: "email@example.com", Scan: []byte("scan")},
+		},
+		{
+			val: func() any {
+				return &Session{ID: "abc", Email: "email@example.com", IPAddr: netip.MustParseAddr("169.251.207.194"), Agent: "curl"}
+			},
+			want: &Session{ID: "abc", Email: "email@example.com", IPAddr: netip.MustParseAddr("169.251.207.194"), Agent: "curl"},
+		},
+	}
+
+	vault := tokenize.NewMemoryVault()
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			for _, fn := range []func(any, tokenize.Vault) error{tokenize.Tokenize, tokenize.Detokenize} {
+				val := tc.val()
+				if err := fn(val, vault); !errors.Is(err, sensitive.ErrUnsupportedFieldType) {
+					t.Fatalf("expect err is %v, got %v", sensitive.ErrUnsupportedFieldType, err)
+				}
+				// the struct is rejected as a whole rather than partially tokenized.
+				if !reflect.DeepEqual(tc.want, val) {
+					t.Fatalf("want %+v, got %+v", tc.want, val)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package tokenize
+
+import (
+	"context"
+	"sync"
+)
+
+// Vault stores the original values of tokenized data, scoped by subject.
+type Vault interface {
+	// Store stores the original value associated with the given token in the subject's scope.
+	Store(ctx context.Context, subjectID, token, value string) error
+
+	// Load returns the original value associated with the given token in the subject's scope.
+	// It returns [ErrTokenNotFound] if the token does not exist.
+	Load(ctx context.Context, subjectID, token string) (string, error)
+
+	// Purge deletes all the tokens of the given subject.
+	// It does not fail if the subject does not have tokens.
+	Purge(ctx context.Context, subjectID string) error
+}
+
+// MemoryVault is an in-memory implementation of [Vault].
+// It is mainly intended for testing purposes.
+type MemoryVault struct {
+	tokens map[string]map[string]string
+	mu     sync.RWMutex
+}
+
+var _ Vault = &MemoryVault{}
+
+// NewMemoryVault returns an empty in-memory vault.
+func NewMemoryVault() *MemoryVault {
+	return &MemoryVault{
+		tokens: make(map[string]map[string]string),
+	}
+}
+
+// Store implements Vault.
+func (v *MemoryVault) Store(_ context.Context, subjectID, token, value string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.tokens[subjectID]; !ok {
+		v.tokens[subjectID] = make(map[string]string)
+	}
+	v.tokens[subjectID][token] = value
+	return nil
+}
+
+// Load implements Vault.
+func (v *MemoryVault) Load(_ context.Context, subjectID, token string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	value, ok := v.tokens[subjectID][token]
+	if !ok {
+		return "", ErrTokenNotFound
+	}
+	return value, nil
+}
+
+// Purge implements Vault.
+func (v *MemoryVault) Purge(_ context.Context, subjectID string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.tokens, subjectID)
+	return nil
+}
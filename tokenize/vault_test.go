@@ -0,0 +1,65 @@
+package tokenize
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// testVault runs the common vault test suite.
+func testVault(t *testing.T, v Vault) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if _, err := v.Load(ctx, "abc", "tok_1"); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expect err is %v, got %v", ErrTokenNotFound, err)
+	}
+
+	for _, e := range []struct{ subjectID, token, value string }{
+		{"abc", "tok_1", "email@example.com"},
+		{"abc", "tok_2", "Guadalupe Kemmer DDS"},
+		{"def", "tok_1", "email.bar@example.com"},
+	} {
+		if err := v.Store(ctx, e.subjectID, e.token, e.value); err != nil {
+			t.Fatal("expect err be nil, got", err)
+		}
+	}
+
+	value, err := v.Load(ctx, "abc", "tok_1")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want := "email@example.com"; value != want {
+		t.Fatalf("want %s, got %s", want, value)
+	}
+	value, err = v.Load(ctx, "def", "tok_1")
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if want := "email.bar@example.com"; value != want {
+		t.Fatalf("want %s, got %s", want, value)
+	}
+	if _, err := v.Load(ctx, "def", "tok_2"); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expect err is %v, got %v", ErrTokenNotFound, err)
+	}
+
+	if err := v.Purge(ctx, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := v.Purge(ctx, "abc"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	for _, token := range []string{"tok_1", "tok_2"} {
+		if _, err := v.Load(ctx, "abc", token); !errors.Is(err, ErrTokenNotFound) {
+			t.Fatalf("expect err is %v, got %v", ErrTokenNotFound, err)
+		}
+	}
+	if _, err := v.Load(ctx, "def", "tok_1"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+}
+
+func TestMemoryVault(t *testing.T) {
+	testVault(t, NewMemoryVault())
+}
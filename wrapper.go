@@ -0,0 +1,126 @@
+package sensitive
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrInvalidWrapper = errors.New("invalid sensitive wrapper type")
+)
+
+// wrapper describes a nullable wrapper type, e.g. [sql.NullString], whose value field
+// holds the sensitive data as long as its valid field is true.
+type wrapper struct {
+	value     []int
+	valid     []int
+	valueType reflect.Type
+}
+
+var (
+	// wrappers are the registered wrapper types; it is guarded by cacheMu since it is used by the scan.
+	wrappers = map[reflect.Type]wrapper{}
+)
+
+func init() {
+	for rt, field := range map[reflect.Type]string{
+		reflect.TypeFor[sql.NullString]():  "String",
+		reflect.TypeFor[sql.NullInt64]():   "Int64",
+		reflect.TypeFor[sql.NullInt32]():   "Int32",
+		reflect.TypeFor[sql.NullInt16]():   "Int16",
+		reflect.TypeFor[sql.NullByte]():    "Byte",
+		reflect.TypeFor[sql.NullFloat64](): "Float64",
+		reflect.TypeFor[sql.NullBool]():    "Bool",
+		reflect.TypeFor[sql.NullTime]():    "Time",
+	} {
+		w, err := newWrapper(rt, field, "Valid")
+		if err != nil {
+			panic(err)
+		}
+		wrappers[rt] = w
+	}
+}
+
+// RegisterWrapper registers the nullable wrapper type W, e.g. a `Option[string]` generic type,
+// so that 'data' fields of this type are replaced through their value field, provided that their valid field is true.
+// If validField is empty, the value field is always replaced.
+//
+//	type Option[T any] struct {
+//		Value T
+//		Some  bool
+//	}
+//
+//	_ = sensitive.RegisterWrapper[Option[string]]("Value", "Some")
+//
+// Generic wrapper types must be registered for each type argument. The [sql.NullString] family of types
+// and [sql.Null] are supported out of the box.
+//
+// RegisterWrapper resets the internal cache of scanned struct types.
+// It is intended to be called at program initialization, before any struct is scanned.
+func RegisterWrapper[W any](valueField, validField string) error {
+	w, err := newWrapper(reflect.TypeFor[W](), valueField, validField)
+	if err != nil {
+		return err
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	wrappers[reflect.TypeFor[W]()] = w
+	cache = make(map[reflect.Type]*sensitiveStructType)
+	return nil
+}
+
+func newWrapper(rt reflect.Type, valueField, validField string) (wrapper, error) {
+	if rt.Kind() != reflect.Struct {
+		return wrapper{}, fmt.Errorf("%w: '%v' is not a struct", ErrInvalidWrapper, rt)
+	}
+	value, ok := rt.FieldByName(valueField)
+	if !ok || !value.IsExported() {
+		return wrapper{}, fmt.Errorf("%w: exported field '%s' not found in '%v'", ErrInvalidWrapper, valueField, rt)
+	}
+	w := wrapper{
+		value:     value.Index,
+		valueType: value.Type,
+	}
+	if validField != "" {
+		valid, ok := rt.FieldByName(validField)
+		if !ok || !valid.IsExported() || valid.Type.Kind() != reflect.Bool {
+			return wrapper{}, fmt.Errorf("%w: exported bool field '%s' not found in '%v'", ErrInvalidWrapper, validField, rt)
+		}
+		w.valid = valid.Index
+	}
+	return w, nil
+}
+
+// wrapperOf returns the wrapper of the given type if it is either registered or an instance of [sql.Null].
+// The caller must hold cacheMu.
+func wrapperOf(rt reflect.Type) (wrapper, bool) {
+	if w, ok := wrappers[rt]; ok {
+		return w, true
+	}
+	if rt.Kind() == reflect.Struct && rt.PkgPath() == "database/sql" && strings.HasPrefix(rt.Name(), "Null[") {
+		if w, err := newWrapper(rt, "V", "Valid"); err == nil {
+			return w, true
+		}
+	}
+	return wrapper{}, false
+}
+
+// unwrap returns the value held by the given wrapper value; it returns false if the wrapper is not valid.
+func (w wrapper) unwrap(v reflect.Value) (reflect.Value, bool) {
+	if w.valid != nil && !v.FieldByIndex(w.valid).Bool() {
+		return reflect.Value{}, false
+	}
+	v = v.FieldByIndex(w.value)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
@@ -0,0 +1,126 @@
+package sensitive
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type optional[T any] struct {
+	Value T
+	Some  bool
+}
+
+type value[T any] struct {
+	V T
+}
+
+func TestRedact_Wrapper(t *testing.T) {
+	if err := RegisterWrapper[optional[string]]("Value", "Some"); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if err := RegisterWrapper[value[*string]]("V", ""); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+
+	type T struct {
+		Email     sql.NullString            `sensitive:"data"`
+		Phone     *sql.NullString           `sensitive:"data"`
+		Nickname  sql.NullString            `sensitive:"data"`
+		Age       sql.NullInt64             `sensitive:"data"`
+		BirthDate sql.NullTime              `sensitive:"data,truncate=year"`
+		Fullname  sql.Null[string]          `sensitive:"data"`
+		Aliases   []sql.Null[string]        `sensitive:"data"`
+		Contacts  map[string]sql.NullString `sensitive:"data"`
+		Address   optional[string]          `sensitive:"data"`
+		City      optional[string]          `sensitive:"data"`
+		Zip       value[*string]            `sensitive:"data"`
+	}
+
+	birthDate := time.Date(1987, time.June, 14, 10, 30, 0, 0, time.UTC)
+
+	val := &T{
+		Email:     sql.NullString{String: "email", Valid: true},
+		Phone:     &sql.NullString{String: "250-308", Valid: true},
+		Nickname:  sql.NullString{String: "Kenna", Valid: false},
+		Age:       sql.NullInt64{Int64: 36, Valid: true},
+		BirthDate: sql.NullTime{Time: birthDate, Valid: true},
+		Fullname:  sql.Null[string]{V: "Eric", Valid: true},
+		Aliases:   []sql.Null[string]{{V: "Eric", Valid: true}, {V: "Rick"}},
+		Contacts:  map[string]sql.NullString{"home": {String: "email", Valid: true}},
+		Address:   optional[string]{Value: "Street", Some: true},
+		City:      optional[string]{Value: "Paris"},
+		Zip:       value[*string]{V: ptr("75001")},
+	}
+	want := &T{
+		Email:     sql.NullString{String: "*****", Valid: true},
+		Phone:     &sql.NullString{String: "*******", Valid: true},
+		Nickname:  sql.NullString{String: "Kenna", Valid: false},
+		Age:       sql.NullInt64{Int64: 0, Valid: true},
+		BirthDate: sql.NullTime{Time: time.Date(1987, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Fullname:  sql.Null[string]{V: "****", Valid: true},
+		Aliases:   []sql.Null[string]{{V: "****", Valid: true}, {V: "Rick"}},
+		Contacts:  map[string]sql.NullString{"home": {String: "*****", Valid: true}},
+		Address:   optional[string]{Value: "******", Some: true},
+		City:      optional[string]{Value: "Paris"},
+		Zip:       value[*string]{V: ptr("*****")},
+	}
+
+	if err := Redact(val); err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	if !reflect.DeepEqual(want, val) {
+		t.Fatalf("want %+v, got %+v", want, val)
+	}
+
+	s, err := Inspect(*val, false)
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	values, err := s.Collect()
+	if err != nil {
+		t.Fatal("expect err be nil, got", err)
+	}
+	for _, v := range values {
+		if v.Path == "Email" && v.RType != reflect.TypeFor[string]() {
+			t.Fatalf("expect RType be the wrapped value type, got %v", v.RType)
+		}
+	}
+}
+
+func TestRegisterWrapper(t *testing.T) {
+	type tc struct {
+		register func() error
+		err      error
+	}
+
+	tcs := []tc{
+		{
+			register: func() error { return RegisterWrapper[string]("Value", "Valid") },
+			err:      ErrInvalidWrapper,
+		},
+		{
+			register: func() error { return RegisterWrapper[optional[int]]("Val", "Some") },
+			err:      ErrInvalidWrapper,
+		},
+		{
+			register: func() error { return RegisterWrapper[optional[int]]("Value", "Value") },
+			err:      ErrInvalidWrapper,
+		},
+		{
+			register: func() error { return RegisterWrapper[optional[int]]("Value", "Some") },
+			err:      nil,
+		},
+	}
+
+	for i, tc := range tcs {
+		t.Run("tc: "+strconv.Itoa(i+1), func(t *testing.T) {
+			if err := tc.register(); !errors.Is(err, tc.err) {
+				t.Fatalf("expect err is %v, got %v", tc.err, err)
+			}
+		})
+	}
+}